@@ -19,12 +19,15 @@ package main
 import (
 	"flag"
 	"os"
+	"time"
 
+	certmanv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta1"
 	workv1 "open-cluster-management.io/api/work/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/dynamic"
@@ -36,30 +39,40 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/cmd/gateway_controller/ocm"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/controllers/gateway"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/envelope"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/policysync"
 	//+kubebuilder:scaffold:imports
 )
 
 var (
-	metricsAddr          string
-	enableLeaderElection bool
-	probeAddr            string
+	metricsAddr              string
+	enableLeaderElection     bool
+	probeAddr                string
+	encryptSecrets           bool
+	certificateExpiryWarning time.Duration
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme.Scheme))
 
 	utilruntime.Must(gatewayapiv1.AddToScheme(scheme.Scheme))
+	utilruntime.Must(gatewayapiv1beta1.AddToScheme(scheme.Scheme))
+	utilruntime.Must(certmanv1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(clusterv1beta2.AddToScheme(scheme.Scheme))
 	utilruntime.Must(workv1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(clusterv1.AddToScheme(scheme.Scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme.Scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme.Scheme))
 
 	//+kubebuilder:scaffold:scheme
 }
@@ -70,6 +83,10 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&certificateExpiryWarning, "certificate-expiry-warning", gateway.DefaultCertificateExpiryWarning,
+		"How long before a certificate placed on a spoke cluster expires to emit a warning event for its gateway.")
+	flag.BoolVar(&encryptSecrets, "encrypt-secrets", false,
+		"Encrypt the secrets placed on spoke clusters with the key published by the kuadrant addon agent of each cluster.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -94,7 +111,18 @@ func main() {
 		os.Exit(1)
 	}
 
-	placer := placement.NewOCMPlacer(mgr.GetClient())
+	placerOpts := []placement.OCMPlacerOption{placement.WithEventRecorder(mgr.GetEventRecorderFor("gateway-controller"))}
+	if encryptSecrets {
+		// the payload hashes are keyed with a key only held in memory, secrets are encrypted again once after a restart
+		hashKey, err := envelope.GenerateHashKey()
+		if err != nil {
+			setupLog.Error(err, "unable to generate the secret payload hash key")
+			os.Exit(1)
+		}
+		placerOpts = append(placerOpts, placement.WithSecretEncryption(hashKey))
+	}
+	placer := placement.NewOCMPlacer(mgr.GetClient(), placerOpts...)
+	metrics.Registry.MustRegister(placement.NewClusterCollector(mgr.GetClient(), mgr.GetAPIReader()))
 	if err = (&gateway.GatewayClassReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -103,6 +131,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&gateway.GatewayClassParametersReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GatewayClassParameters")
+		os.Exit(1)
+	}
+
 	dynamicClient := dynamic.NewForConfigOrDie(mgr.GetConfig())
 	dynamicInformerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
 		dynamicClient,
@@ -118,12 +154,15 @@ func main() {
 	}
 
 	if err = (&gateway.GatewayReconciler{
-		Client:                 mgr.GetClient(),
-		Scheme:                 mgr.GetScheme(),
-		Placement:              placer,
-		PolicyInformersManager: policyInformersManager,
-		DynamicClient:          dynamicClient,
-		WatchedPolicies:        map[schema.GroupVersionResource]cache.ResourceEventHandlerRegistration{},
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		Placement:                placer,
+		PolicyInformersManager:   policyInformersManager,
+		DynamicClient:            dynamicClient,
+		WatchedPolicies:          map[schema.GroupVersionResource]cache.ResourceEventHandlerRegistration{},
+		Recorder:                 mgr.GetEventRecorderFor("gateway-controller"),
+		CertificateExpiryWarning: certificateExpiryWarning,
+		EncryptSecrets:           encryptSecrets,
 	}).SetupWithManager(mgr, ctx); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Gateway")
 		os.Exit(1)
@@ -18,6 +18,7 @@ import (
 
 	kuadrantv1beta1 "github.com/kuadrant/kuadrant-operator/api/v1beta1"
 
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/env"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/hub"
 )
 
@@ -80,6 +81,7 @@ func GetDefaultValues(cluster *clusterv1.ManagedCluster,
 	defaultCatalog := "operatorhubio-catalog"
 	defaultCatalogNS := "olm"
 	defaultChannel := "stable"
+	defaultAgentImage := env.GetEnvString("ADDON_AGENT_IMAGE", "quay.io/kuadrant/multicluster-gateway-controller-addon-agent:main")
 
 	manifestConfig := struct {
 		IstioOperator          string
@@ -89,6 +91,7 @@ func GetDefaultValues(cluster *clusterv1.ManagedCluster,
 		CatalogSource          string
 		CatalogSourceNS        string
 		Channel                string
+		AgentImage             string
 	}{
 		ClusterName:            cluster.Name,
 		IstioOperator:          defaultIstioOperator,
@@ -97,6 +100,7 @@ func GetDefaultValues(cluster *clusterv1.ManagedCluster,
 		CatalogSource:          defaultCatalog,
 		CatalogSourceNS:        defaultCatalogNS,
 		Channel:                defaultChannel,
+		AgentImage:             defaultAgentImage,
 	}
 
 	return addonfactory.StructToValues(manifestConfig), nil
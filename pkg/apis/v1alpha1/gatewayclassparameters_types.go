@@ -0,0 +1,242 @@
+/*
+Copyright 2022 The MultiCluster Traffic Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GatewayClassParametersSpec defines the parameters of the GatewayClasses that reference it
+type GatewayClassParametersSpec struct {
+	// DownstreamClass specifies what GatewayClassName to set in the
+	// downstream clusters.
+	// +kubebuilder:default=istio
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	DownstreamClass string `json:"downstreamClass,omitempty"`
+
+	// DownstreamClasses selects the GatewayClassName set in the downstream
+	// clusters by the labels and cluster claims of their ManagedCluster. The
+	// first matching entry is used and DownstreamClass when none match
+	// +optional
+	DownstreamClasses []ClusterDownstreamClass `json:"downstreamClasses,omitempty"`
+
+	// PoliciesToSync specifies a list of Policy GVRs that will be watched
+	// in the hub and synced to the spokes
+	// +optional
+	PoliciesToSync []PolicyGroupVersionResource `json:"experimentalPolicySync,omitempty"`
+
+	// DownstreamNamespace specifies the namespace the gateway is placed in on
+	// the spokes. It is either a fixed namespace or a template with the
+	// .Namespace and .Name of the upstream gateway. When set the namespace is
+	// created if missing but is never updated or deleted by the controller.
+	// Defaults to kuadrant-{{.Namespace}}
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	DownstreamNamespace string `json:"downstreamNamespace,omitempty"`
+
+	// DownstreamMetadata filters the labels and annotations propagated from
+	// the hub to the gateways, secrets and policies placed on the spokes and
+	// specifies extra ones to inject
+	// +optional
+	DownstreamMetadata *DownstreamMetadata `json:"downstreamMetadata,omitempty"`
+
+	// ClusterOverrides patches the downstream gateway placed on the clusters
+	// selected by each override. Overrides are applied in order
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ClusterOverrides []ClusterOverride `json:"clusterOverrides,omitempty"`
+
+	// RequiredFeatures lists the CRDs, for example authpolicies.kuadrant.io,
+	// that must be installed on a spoke reporting its capabilities for the
+	// gateway to be placed on it
+	// +listType=set
+	// +optional
+	RequiredFeatures []string `json:"requiredFeatures,omitempty"`
+
+	// MinGatewayAPIVersion is the oldest Gateway API version of the spokes
+	// reporting their capabilities that the gateway is placed on, for example
+	// v1.0.0
+	// +kubebuilder:validation:Pattern=`^v?[0-9]+(\.[0-9]+)*$`
+	// +optional
+	MinGatewayAPIVersion string `json:"minGatewayAPIVersion,omitempty"`
+
+	// Addresses defines how the spec.addresses of the gateway are assigned
+	// to the clusters it is placed on
+	// +optional
+	Addresses *AddressAssignment `json:"addresses,omitempty"`
+
+	// Infrastructure defines whether the spec.infrastructure of the gateway
+	// is placed on the clusters
+	// +optional
+	Infrastructure *InfrastructurePropagation `json:"infrastructure,omitempty"`
+}
+
+// AddressAssignment defines how the spec.addresses of the gateway are
+// assigned to the clusters. Addresses of a type that is not supported, or
+// with an invalid value, are not placed
+type AddressAssignment struct {
+	// Mode is Copy to place every address on every cluster, Pool to assign a
+	// different address to each cluster, or None to let the clusters assign
+	// their own addresses. Defaults to Copy
+	// +kubebuilder:validation:Enum=Copy;Pool;None
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// SupportedTypes lists the address types that are placed. Defaults to
+	// IPAddress, Hostname and NamedAddress
+	// +optional
+	SupportedTypes []string `json:"supportedTypes,omitempty"`
+}
+
+// InfrastructurePropagation defines whether the spec.infrastructure of the
+// gateway is placed on the clusters
+type InfrastructurePropagation struct {
+	// Mode is Copy to place the infrastructure on every cluster, or None to
+	// leave it out. Defaults to Copy
+	// +kubebuilder:validation:Enum=Copy;None
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+// ClusterDownstreamClass selects the downstream GatewayClassName of the
+// clusters whose ManagedCluster matches both the label selector and the
+// cluster claims
+type ClusterDownstreamClass struct {
+	// DownstreamClass is the GatewayClassName set in the selected clusters
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	DownstreamClass string `json:"downstreamClass"`
+
+	// ClusterSelector selects the clusters by the labels of their ManagedCluster
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// ClusterClaims selects the clusters by the name and value of the cluster
+	// claims reported in the status of their ManagedCluster
+	// +optional
+	ClusterClaims map[string]string `json:"clusterClaims,omitempty"`
+}
+
+// ClusterOverride patches the downstream gateway placed on the clusters it
+// selects by name or by the labels of their ManagedCluster
+type ClusterOverride struct {
+	// Name identifies the override in the cluster status of the gateways
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Clusters lists the names of the clusters the override applies to
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// ClusterSelector selects the clusters the override applies to by the
+	// labels of their ManagedCluster
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// PatchType is the format of the patch, either a JSON merge patch or a
+	// JSON patch. Defaults to merge
+	// +kubebuilder:validation:Enum=merge;json
+	// +optional
+	PatchType string `json:"patchType,omitempty"`
+
+	// Patch is applied to the downstream gateway
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Patch apiextensionsv1.JSON `json:"patch"`
+}
+
+// DownstreamMetadata defines the labels and annotations of the objects placed on the spokes
+type DownstreamMetadata struct {
+	// Labels filters and injects the labels of the downstream objects
+	// +optional
+	Labels MetadataRules `json:"labels,omitempty"`
+
+	// Annotations filters and injects the annotations of the downstream objects
+	// +optional
+	Annotations MetadataRules `json:"annotations,omitempty"`
+}
+
+// MetadataRules filters the keys propagated from the hub and injects extra ones
+type MetadataRules struct {
+	// Allow lists patterns of the keys that are propagated, where * matches
+	// any sequence of characters. When empty every key that is not denied is
+	// propagated
+	// +optional
+	Allow []string `json:"allow,omitempty"`
+
+	// Deny lists patterns of the keys that are never propagated
+	// +optional
+	Deny []string `json:"deny,omitempty"`
+
+	// Inject specifies keys and values added to the downstream objects. They
+	// take precedence over the propagated ones
+	// +optional
+	Inject map[string]string `json:"inject,omitempty"`
+}
+
+// PolicyGroupVersionResource identifies a Policy resource to sync
+type PolicyGroupVersionResource struct {
+	// +kubebuilder:validation:MinLength=1
+	Group string `json:"group"`
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+}
+
+// GatewayClassParametersStatus defines the observed state of GatewayClassParameters
+type GatewayClassParametersStatus struct {
+	// ObservedGeneration reflects the generation of the most recently observed spec.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// GatewayClasses lists the GatewayClasses that reference these parameters
+	// +optional
+	GatewayClasses []string `json:"gatewayClasses,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=gcp
+//+kubebuilder:printcolumn:name="Downstream Class",type=string,JSONPath=`.spec.downstreamClass`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// GatewayClassParameters is the Schema for the gatewayclassparameters API
+type GatewayClassParameters struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GatewayClassParametersSpec   `json:"spec,omitempty"`
+	Status GatewayClassParametersStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GatewayClassParametersList contains a list of GatewayClassParameters
+type GatewayClassParametersList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayClassParameters `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GatewayClassParameters{}, &GatewayClassParametersList{})
+}
@@ -0,0 +1,317 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+)
+
+const (
+	// ClusterAddressesAnnotation assigns addresses to named clusters. It holds a JSON object mapping the name of a
+	// cluster to the addresses that replace the spec.addresses of the gateway placed on it
+	ClusterAddressesAnnotation = LabelPrefix + "cluster-addresses"
+	// AssignedAddressesAnnotation records the pool address assigned to each cluster so that a cluster keeps its
+	// address when other clusters are added or removed
+	AssignedAddressesAnnotation = LabelPrefix + "assigned-addresses"
+
+	// AddressesAssignedConditionType reports whether the addresses of the gateway are valid, assigned and accepted
+	// by the clusters the gateway is placed on
+	AddressesAssignedConditionType = LabelPrefix + "AddressesAssigned"
+
+	AddressesAssignedReason     = "AddressesAssigned"
+	AddressPoolExhaustedReason  = "AddressPoolExhausted"
+	AddressesNotSatisfiedReason = "AddressesNotSatisfied"
+)
+
+// AddressAssignmentMode defines how the spec.addresses of the gateway are assigned to the clusters
+type AddressAssignmentMode string
+
+const (
+	// CopyAddresses places every address on every cluster. It is the default mode
+	CopyAddresses AddressAssignmentMode = "Copy"
+	// PoolAddresses assigns a different address of the gateway to each cluster
+	PoolAddresses AddressAssignmentMode = "Pool"
+	// NoAddresses places no address, the clusters assign their own
+	NoAddresses AddressAssignmentMode = "None"
+)
+
+// InfrastructurePropagationMode defines whether the spec.infrastructure of the gateway is placed on the clusters
+type InfrastructurePropagationMode string
+
+const (
+	// CopyInfrastructure places the infrastructure on every cluster. It is the default mode
+	CopyInfrastructure InfrastructurePropagationMode = "Copy"
+	// NoInfrastructure leaves the infrastructure out of the gateways placed on the clusters
+	NoInfrastructure InfrastructurePropagationMode = "None"
+)
+
+// defaultSupportedAddressTypes are the address types placed when the params don't list the supported ones
+var defaultSupportedAddressTypes = []gatewayapiv1.AddressType{
+	gatewayapiv1.IPAddressType,
+	gatewayapiv1.HostnameAddressType,
+	gatewayapiv1.NamedAddressType,
+}
+
+// spokeAddressReasons are the reasons of a downstream Programmed condition reporting an address it can't satisfy
+var spokeAddressReasons = []string{
+	string(gatewayapiv1.GatewayReasonAddressNotAssigned),
+	string(gatewayapiv1.GatewayReasonAddressNotUsable),
+	string(gatewayapiv1.GatewayReasonUnsupportedAddress),
+}
+
+func (p *Params) addressAssignmentMode() AddressAssignmentMode {
+	if p == nil || p.Addresses == nil || p.Addresses.Mode == "" {
+		return CopyAddresses
+	}
+	return AddressAssignmentMode(p.Addresses.Mode)
+}
+
+func (p *Params) supportedAddressTypes() []gatewayapiv1.AddressType {
+	if p == nil || p.Addresses == nil || len(p.Addresses.SupportedTypes) == 0 {
+		return defaultSupportedAddressTypes
+	}
+	return slice.Map(p.Addresses.SupportedTypes, func(addressType string) gatewayapiv1.AddressType {
+		return gatewayapiv1.AddressType(addressType)
+	})
+}
+
+// PropagatesInfrastructure returns whether the spec.infrastructure of the gateway is placed on the clusters
+func (p *Params) PropagatesInfrastructure() bool {
+	return p == nil || p.Infrastructure == nil || InfrastructurePropagationMode(p.Infrastructure.Mode) != NoInfrastructure
+}
+
+func (p *Params) validateAddresses() error {
+	if p.Addresses != nil {
+		switch AddressAssignmentMode(p.Addresses.Mode) {
+		case "", CopyAddresses, PoolAddresses, NoAddresses:
+		default:
+			return fmt.Errorf("unsupported addresses mode %s. Must be one of [%s,%s,%s]", p.Addresses.Mode, CopyAddresses, PoolAddresses, NoAddresses)
+		}
+		for _, addressType := range p.Addresses.SupportedTypes {
+			if addressType == "" {
+				return fmt.Errorf("addresses supportedTypes can't be empty")
+			}
+		}
+	}
+	if p.Infrastructure != nil {
+		switch InfrastructurePropagationMode(p.Infrastructure.Mode) {
+		case "", CopyInfrastructure, NoInfrastructure:
+		default:
+			return fmt.Errorf("unsupported infrastructure mode %s. Must be one of [%s,%s]", p.Infrastructure.Mode, CopyInfrastructure, NoInfrastructure)
+		}
+	}
+	return nil
+}
+
+// addressAssignment is the result of assigning the addresses of a gateway to the clusters it targets
+type addressAssignment struct {
+	mode AddressAssignmentMode
+	// requested is true when the gateway sets addresses for any of the clusters
+	requested bool
+	// supported are the addresses of the gateway with a supported type and a valid value
+	supported []gatewayapiv1.GatewayAddress
+	// overrides are the addresses of the clusters listed in the ClusterAddressesAnnotation
+	overrides map[string][]gatewayapiv1.GatewayAddress
+	// pooled is the pool address assigned to each cluster
+	pooled map[string]gatewayapiv1.GatewayAddress
+	// invalid describes the addresses left out as unsupported or invalid
+	invalid []string
+	// unassigned are the clusters left without a pool address
+	unassigned []string
+}
+
+// assignAddresses assigns the addresses of the gateway to the clusters according to the params. The clusters
+// listed in the ClusterAddressesAnnotation of the gateway get their own addresses whatever the mode
+func (p *Params) assignAddresses(gateway *gatewayapiv1.Gateway, clusters []string) *addressAssignment {
+	supportedTypes := p.supportedAddressTypes()
+	assignment := &addressAssignment{
+		mode:      p.addressAssignmentMode(),
+		requested: len(gateway.Spec.Addresses) > 0,
+		overrides: map[string][]gatewayapiv1.GatewayAddress{},
+		pooled:    map[string]gatewayapiv1.GatewayAddress{},
+	}
+	assignment.supported, assignment.invalid = filterAddresses(gateway.Spec.Addresses, supportedTypes, "spec.addresses")
+
+	if value := metadata.GetAnnotation(gateway, ClusterAddressesAnnotation); value != "" {
+		assignment.requested = true
+		overrides := map[string][]gatewayapiv1.GatewayAddress{}
+		if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+			assignment.invalid = append(assignment.invalid, fmt.Sprintf("annotation %s is invalid: %v", ClusterAddressesAnnotation, err))
+		}
+		for _, cluster := range sets.List(sets.KeySet(overrides)) {
+			valid, invalid := filterAddresses(overrides[cluster], supportedTypes, fmt.Sprintf("cluster %s", cluster))
+			assignment.overrides[cluster] = valid
+			assignment.invalid = append(assignment.invalid, invalid...)
+		}
+	}
+
+	if assignment.mode == PoolAddresses {
+		previous := map[string]string{}
+		// an invalid record is discarded and the pool assigned again
+		_ = json.Unmarshal([]byte(metadata.GetAnnotation(gateway, AssignedAddressesAnnotation)), &previous)
+		poolClusters := slice.Filter(clusters, func(cluster string) bool {
+			_, overridden := assignment.overrides[cluster]
+			return !overridden
+		})
+		assignment.pooled, assignment.unassigned = assignPool(assignment.supported, poolClusters, previous)
+	}
+	return assignment
+}
+
+// assignPool assigns a different address of the pool to each cluster. A cluster keeps the address previously
+// assigned to it while it is in the pool, the other clusters are assigned the free addresses in order
+func assignPool(pool []gatewayapiv1.GatewayAddress, clusters []string, previous map[string]string) (map[string]gatewayapiv1.GatewayAddress, []string) {
+	assigned := map[string]gatewayapiv1.GatewayAddress{}
+	taken := sets.New[string]()
+	sorted := append([]string{}, clusters...)
+	sort.Strings(sorted)
+	for _, cluster := range sorted {
+		value, ok := previous[cluster]
+		if !ok || taken.Has(value) {
+			continue
+		}
+		if address, found := slice.Find(pool, func(address gatewayapiv1.GatewayAddress) bool { return address.Value == value }); found {
+			assigned[cluster] = address
+			taken.Insert(value)
+		}
+	}
+	unassigned := []string{}
+	for _, cluster := range sorted {
+		if _, ok := assigned[cluster]; ok {
+			continue
+		}
+		free := slice.Filter(pool, func(address gatewayapiv1.GatewayAddress) bool {
+			return !taken.Has(address.Value)
+		})
+		if len(free) == 0 {
+			unassigned = append(unassigned, cluster)
+			continue
+		}
+		assigned[cluster] = free[0]
+		taken.Insert(free[0].Value)
+	}
+	return assigned, unassigned
+}
+
+// filterAddresses returns the addresses of a supported type with a valid value, and a description of the others
+func filterAddresses(addresses []gatewayapiv1.GatewayAddress, supportedTypes []gatewayapiv1.AddressType, source string) ([]gatewayapiv1.GatewayAddress, []string) {
+	valid := []gatewayapiv1.GatewayAddress{}
+	invalid := []string{}
+	for _, address := range addresses {
+		addressType := gatewayapiv1.IPAddressType
+		if address.Type != nil {
+			addressType = *address.Type
+		}
+		if !slice.Contains(supportedTypes, slice.EqualsTo(addressType)) {
+			invalid = append(invalid, fmt.Sprintf("%s: unsupported address type %s", source, addressType))
+			continue
+		}
+		if err := validateAddressValue(addressType, address.Value); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: invalid %s %s: %v", source, addressType, address.Value, err))
+			continue
+		}
+		valid = append(valid, address)
+	}
+	return valid, invalid
+}
+
+func validateAddressValue(addressType gatewayapiv1.AddressType, value string) error {
+	switch addressType {
+	case gatewayapiv1.IPAddressType:
+		if net.ParseIP(value) == nil {
+			return fmt.Errorf("not an IP address")
+		}
+	case gatewayapiv1.HostnameAddressType:
+		if errs := validation.IsDNS1123Subdomain(strings.TrimPrefix(value, "*.")); len(errs) > 0 {
+			return fmt.Errorf("%s", strings.Join(errs, ", "))
+		}
+	default:
+		if value == "" {
+			return fmt.Errorf("empty value")
+		}
+	}
+	return nil
+}
+
+// addressesFor returns the addresses of the gateway placed on the cluster
+func (a *addressAssignment) addressesFor(cluster string) []gatewayapiv1.GatewayAddress {
+	var addresses []gatewayapiv1.GatewayAddress
+	if overrides, ok := a.overrides[cluster]; ok {
+		addresses = overrides
+	} else {
+		switch a.mode {
+		case PoolAddresses:
+			if address, ok := a.pooled[cluster]; ok {
+				addresses = []gatewayapiv1.GatewayAddress{address}
+			}
+		case NoAddresses:
+		default:
+			addresses = a.supported
+		}
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+	return addresses
+}
+
+// pooledAnnotation returns the value of the AssignedAddressesAnnotation, empty when no pool address is assigned
+func (a *addressAssignment) pooledAnnotation() (string, error) {
+	if len(a.pooled) == 0 {
+		return "", nil
+	}
+	record := map[string]string{}
+	for cluster, address := range a.pooled {
+		record[cluster] = address.Value
+	}
+	serialized, err := json.Marshal(record)
+	return string(serialized), err
+}
+
+// buildAddressesAssignedCondition reports the addresses that are invalid, can't be assigned from the pool or are
+// reported by the downstream gateways as not satisfied. nil is returned when the gateway sets no addresses
+func buildAddressesAssignedCondition(generation int64, assignment *addressAssignment, clusterConditions map[string][]metav1.Condition) *metav1.Condition {
+	if !assignment.requested {
+		return nil
+	}
+	notSatisfied := []string{}
+	for _, cluster := range sets.List(sets.KeySet(clusterConditions)) {
+		programmed := meta.FindStatusCondition(clusterConditions[cluster], string(gatewayapiv1.GatewayConditionProgrammed))
+		if programmed == nil || programmed.Status != metav1.ConditionFalse || !slice.ContainsString(spokeAddressReasons, programmed.Reason) {
+			continue
+		}
+		notSatisfied = append(notSatisfied, fmt.Sprintf("%s: %s: %s", cluster, programmed.Reason, programmed.Message))
+	}
+	condition := &metav1.Condition{
+		Type:               AddressesAssignedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             AddressesAssignedReason,
+		Message:            "addresses assigned to all clusters",
+		ObservedGeneration: generation,
+	}
+	if len(assignment.invalid) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(gatewayapiv1.GatewayReasonUnsupportedAddress)
+		condition.Message = fmt.Sprintf("addresses not placed: %s", strings.Join(assignment.invalid, "; "))
+	} else if len(assignment.unassigned) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = AddressPoolExhaustedReason
+		condition.Message = fmt.Sprintf("no address left in the pool for clusters: %s", strings.Join(assignment.unassigned, ", "))
+	} else if len(notSatisfied) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = AddressesNotSatisfiedReason
+		condition.Message = fmt.Sprintf("addresses not satisfied: %s", strings.Join(notSatisfied, "; "))
+	}
+	return condition
+}
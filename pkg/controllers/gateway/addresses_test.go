@@ -0,0 +1,385 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/envelope"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func ipAddress(value string) gatewayapiv1.GatewayAddress {
+	addressType := gatewayapiv1.IPAddressType
+	return gatewayapiv1.GatewayAddress{Type: &addressType, Value: value}
+}
+
+func hostnameAddress(value string) gatewayapiv1.GatewayAddress {
+	addressType := gatewayapiv1.HostnameAddressType
+	return gatewayapiv1.GatewayAddress{Type: &addressType, Value: value}
+}
+
+func addressedGateway(annotations map[string]string, addresses ...gatewayapiv1.GatewayAddress) *gatewayapiv1.Gateway {
+	return &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Annotations: annotations},
+		Spec:       gatewayapiv1.GatewaySpec{Addresses: addresses},
+	}
+}
+
+func TestAssignAddresses(t *testing.T) {
+	clusters := []string{"c1", "c2"}
+	cases := []struct {
+		name               string
+		params             *Params
+		gateway            *gatewayapiv1.Gateway
+		expectedAddresses  map[string][]gatewayapiv1.GatewayAddress
+		expectedRequested  bool
+		expectedInvalid    int
+		expectedUnassigned []string
+	}{
+		{
+			name:              "no addresses",
+			params:            &Params{},
+			gateway:           addressedGateway(nil),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{"c1": nil, "c2": nil},
+		},
+		{
+			name:    "addresses copied by default",
+			params:  &Params{},
+			gateway: addressedGateway(nil, ipAddress("10.0.0.1"), hostnameAddress("gw.example.com")),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{
+				"c1": {ipAddress("10.0.0.1"), hostnameAddress("gw.example.com")},
+				"c2": {ipAddress("10.0.0.1"), hostnameAddress("gw.example.com")},
+			},
+			expectedRequested: true,
+		},
+		{
+			name:              "addresses not placed",
+			params:            &Params{Addresses: &v1alpha1.AddressAssignment{Mode: string(NoAddresses)}},
+			gateway:           addressedGateway(nil, ipAddress("10.0.0.1")),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{"c1": nil, "c2": nil},
+			expectedRequested: true,
+		},
+		{
+			name:    "pool assigned in order",
+			params:  &Params{Addresses: &v1alpha1.AddressAssignment{Mode: string(PoolAddresses)}},
+			gateway: addressedGateway(nil, ipAddress("10.0.0.1"), ipAddress("10.0.0.2")),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{
+				"c1": {ipAddress("10.0.0.1")},
+				"c2": {ipAddress("10.0.0.2")},
+			},
+			expectedRequested: true,
+		},
+		{
+			name:   "pool keeps the previous assignments",
+			params: &Params{Addresses: &v1alpha1.AddressAssignment{Mode: string(PoolAddresses)}},
+			gateway: addressedGateway(map[string]string{AssignedAddressesAnnotation: `{"c2":"10.0.0.1","c3":"10.0.0.2"}`},
+				ipAddress("10.0.0.1"), ipAddress("10.0.0.2")),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{
+				"c1": {ipAddress("10.0.0.2")},
+				"c2": {ipAddress("10.0.0.1")},
+			},
+			expectedRequested: true,
+		},
+		{
+			name:    "pool exhausted",
+			params:  &Params{Addresses: &v1alpha1.AddressAssignment{Mode: string(PoolAddresses)}},
+			gateway: addressedGateway(nil, ipAddress("10.0.0.1")),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{
+				"c1": {ipAddress("10.0.0.1")},
+				"c2": nil,
+			},
+			expectedRequested:  true,
+			expectedUnassigned: []string{"c2"},
+		},
+		{
+			name:   "cluster addresses override the mode",
+			params: &Params{Addresses: &v1alpha1.AddressAssignment{Mode: string(PoolAddresses)}},
+			gateway: addressedGateway(map[string]string{ClusterAddressesAnnotation: `{"c1":[{"type":"Hostname","value":"c1.example.com"}]}`},
+				ipAddress("10.0.0.1")),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{
+				"c1": {hostnameAddress("c1.example.com")},
+				"c2": {ipAddress("10.0.0.1")},
+			},
+			expectedRequested: true,
+		},
+		{
+			name:              "unsupported address type",
+			params:            &Params{Addresses: &v1alpha1.AddressAssignment{SupportedTypes: []string{string(gatewayapiv1.IPAddressType)}}},
+			gateway:           addressedGateway(nil, ipAddress("10.0.0.1"), hostnameAddress("gw.example.com")),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{"c1": {ipAddress("10.0.0.1")}, "c2": {ipAddress("10.0.0.1")}},
+			expectedRequested: true,
+			expectedInvalid:   1,
+		},
+		{
+			name:              "invalid address values",
+			params:            &Params{},
+			gateway:           addressedGateway(nil, ipAddress("not-an-ip"), hostnameAddress("Not_A_Hostname")),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{"c1": nil, "c2": nil},
+			expectedRequested: true,
+			expectedInvalid:   2,
+		},
+		{
+			name:              "invalid cluster addresses annotation",
+			params:            &Params{},
+			gateway:           addressedGateway(map[string]string{ClusterAddressesAnnotation: "not json"}),
+			expectedAddresses: map[string][]gatewayapiv1.GatewayAddress{"c1": nil, "c2": nil},
+			expectedRequested: true,
+			expectedInvalid:   1,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assignment := testCase.params.assignAddresses(testCase.gateway, clusters)
+			for cluster, expected := range testCase.expectedAddresses {
+				if got := assignment.addressesFor(cluster); !reflect.DeepEqual(got, expected) {
+					t.Errorf("expected addresses %v for cluster %s but got %v", expected, cluster, got)
+				}
+			}
+			if assignment.requested != testCase.expectedRequested {
+				t.Errorf("expected requested %v but got %v", testCase.expectedRequested, assignment.requested)
+			}
+			if len(assignment.invalid) != testCase.expectedInvalid {
+				t.Errorf("expected %d invalid addresses but got %v", testCase.expectedInvalid, assignment.invalid)
+			}
+			if len(assignment.unassigned) != len(testCase.expectedUnassigned) ||
+				(len(testCase.expectedUnassigned) > 0 && !reflect.DeepEqual(assignment.unassigned, testCase.expectedUnassigned)) {
+				t.Errorf("expected unassigned clusters %v but got %v", testCase.expectedUnassigned, assignment.unassigned)
+			}
+		})
+	}
+}
+
+func TestPooledAnnotation(t *testing.T) {
+	params := &Params{Addresses: &v1alpha1.AddressAssignment{Mode: string(PoolAddresses)}}
+	assignment := params.assignAddresses(addressedGateway(nil, ipAddress("10.0.0.1"), ipAddress("10.0.0.2")), []string{"c1", "c2"})
+	annotation, err := assignment.pooledAnnotation()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if expected := `{"c1":"10.0.0.1","c2":"10.0.0.2"}`; annotation != expected {
+		t.Errorf("expected annotation %s but got %s", expected, annotation)
+	}
+
+	copied := (&Params{}).assignAddresses(addressedGateway(nil, ipAddress("10.0.0.1")), []string{"c1"})
+	if annotation, _ := copied.pooledAnnotation(); annotation != "" {
+		t.Errorf("expected no annotation without a pool but got %s", annotation)
+	}
+}
+
+func TestValidateAddresses(t *testing.T) {
+	cases := []struct {
+		name        string
+		params      *Params
+		expectError bool
+	}{
+		{
+			name:   "defaults",
+			params: &Params{},
+		},
+		{
+			name: "valid modes",
+			params: &Params{
+				Addresses:      &v1alpha1.AddressAssignment{Mode: string(PoolAddresses), SupportedTypes: []string{string(gatewayapiv1.IPAddressType)}},
+				Infrastructure: &v1alpha1.InfrastructurePropagation{Mode: string(NoInfrastructure)},
+			},
+		},
+		{
+			name:        "unsupported addresses mode",
+			params:      &Params{Addresses: &v1alpha1.AddressAssignment{Mode: "Random"}},
+			expectError: true,
+		},
+		{
+			name:        "empty supported type",
+			params:      &Params{Addresses: &v1alpha1.AddressAssignment{SupportedTypes: []string{""}}},
+			expectError: true,
+		},
+		{
+			name:        "unsupported infrastructure mode",
+			params:      &Params{Infrastructure: &v1alpha1.InfrastructurePropagation{Mode: "Merge"}},
+			expectError: true,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.params.validateAddresses()
+			if testCase.expectError && err == nil {
+				t.Errorf("expected an error but got none")
+			}
+			if !testCase.expectError && err != nil {
+				t.Errorf("did not expect an error but got %v", err)
+			}
+		})
+	}
+}
+
+func TestPropagatesInfrastructure(t *testing.T) {
+	if !(&Params{}).PropagatesInfrastructure() {
+		t.Errorf("expected the infrastructure propagated by default")
+	}
+	if (&Params{Infrastructure: &v1alpha1.InfrastructurePropagation{Mode: string(NoInfrastructure)}}).PropagatesInfrastructure() {
+		t.Errorf("did not expect the infrastructure propagated")
+	}
+}
+
+func TestBuildAddressesAssignedCondition(t *testing.T) {
+	cases := []struct {
+		name              string
+		assignment        *addressAssignment
+		clusterConditions map[string][]metav1.Condition
+		expectNil         bool
+		expectedStatus    metav1.ConditionStatus
+		expectedReason    string
+	}{
+		{
+			name:       "no addresses requested",
+			assignment: &addressAssignment{},
+			expectNil:  true,
+		},
+		{
+			name:           "addresses assigned",
+			assignment:     &addressAssignment{requested: true},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: AddressesAssignedReason,
+		},
+		{
+			name:           "invalid addresses",
+			assignment:     &addressAssignment{requested: true, invalid: []string{"spec.addresses: unsupported address type Hostname"}, unassigned: []string{"c1"}},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: string(gatewayapiv1.GatewayReasonUnsupportedAddress),
+		},
+		{
+			name:           "pool exhausted",
+			assignment:     &addressAssignment{requested: true, unassigned: []string{"c1"}},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: AddressPoolExhaustedReason,
+		},
+		{
+			name:       "address not usable on a spoke",
+			assignment: &addressAssignment{requested: true},
+			clusterConditions: map[string][]metav1.Condition{
+				"c1": {{Type: string(gatewayapiv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue, Reason: "Programmed"}},
+				"c2": {{Type: string(gatewayapiv1.GatewayConditionProgrammed), Status: metav1.ConditionFalse, Reason: string(gatewayapiv1.GatewayReasonAddressNotUsable)}},
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: AddressesNotSatisfiedReason,
+		},
+		{
+			name:       "spoke not programmed for another reason",
+			assignment: &addressAssignment{requested: true},
+			clusterConditions: map[string][]metav1.Condition{
+				"c1": {{Type: string(gatewayapiv1.GatewayConditionProgrammed), Status: metav1.ConditionFalse, Reason: "Pending"}},
+			},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: AddressesAssignedReason,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			condition := buildAddressesAssignedCondition(2, testCase.assignment, testCase.clusterConditions)
+			if testCase.expectNil {
+				if condition != nil {
+					t.Errorf("expected no condition but got %v", condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("expected a condition but got nil")
+			}
+			if condition.Status != testCase.expectedStatus || condition.Reason != testCase.expectedReason {
+				t.Errorf("expected status %s and reason %s but got %s and %s", testCase.expectedStatus, testCase.expectedReason, condition.Status, condition.Reason)
+			}
+			if condition.ObservedGeneration != 2 {
+				t.Errorf("expected observed generation 2 but got %d", condition.ObservedGeneration)
+			}
+		})
+	}
+}
+
+func TestClusterCustomiserAddresses(t *testing.T) {
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	r := &GatewayReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+	params := &Params{Addresses: &v1alpha1.AddressAssignment{Mode: string(PoolAddresses)}}
+	upstream := addressedGateway(nil, ipAddress("10.0.0.1"), ipAddress("10.0.0.2"))
+	customise := r.clusterCustomiser(params, params.assignAddresses(upstream, []string{"c1", "c2"}), nil)
+	for cluster, expected := range map[string]string{"c1": "10.0.0.1", "c2": "10.0.0.2"} {
+		downstream := upstream.DeepCopy()
+		if err := customise(context.TODO(), cluster, downstream); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if len(downstream.Spec.Addresses) != 1 || downstream.Spec.Addresses[0].Value != expected {
+			t.Errorf("expected address %s for cluster %s but got %v", expected, cluster, downstream.Spec.Addresses)
+		}
+	}
+}
+
+func TestReconcileDownstreamAddressAssignment(t *testing.T) {
+	key, err := envelope.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashKey, err := envelope.GenerateHashKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway := addressedGateway(nil, ipAddress("10.0.0.1"))
+	gateway.Labels = getTestGatewayLabels()
+	gateway.Spec.Listeners = []gatewayapiv1.Listener{{
+		Name:     "https",
+		Protocol: gatewayapiv1.HTTPSProtocolType,
+		TLS: &gatewayapiv1.GatewayTLSConfig{
+			CertificateRefs: []gatewayapiv1.SecretObjectReference{{Name: testutil.TLSSecretName}},
+		},
+	}}
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = clusterv1beta1.AddToScheme(scheme)
+	_ = workv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			reportingCluster("c1", map[string]string{envelope.PublicKeyClaim: envelope.EncodePublicKey(key.PublicKey())}),
+			// c2 has not published an encryption key so the gateway placing secrets is not placed on it
+			reportingCluster("c2", map[string]string{}),
+			buildTestPlacementDecision(gateway.Namespace, testutil.Placement, "c1", "c2"),
+		).
+		WithLists(getValidTLSCertificateSecretList(testutil.TLSSecretName, gateway.Namespace)).
+		Build()
+	r := &GatewayReconciler{
+		Client:         c,
+		Scheme:         scheme,
+		Placement:      placement.NewOCMPlacer(c, placement.WithSecretEncryption(hashKey)),
+		EncryptSecrets: true,
+	}
+
+	params := &Params{Addresses: &v1alpha1.AddressAssignment{Mode: string(PoolAddresses)}}
+	_, _, _, _, addresses, err := r.reconcileDownstreamFromUpstreamGateway(context.TODO(), gateway, params)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if addresses == nil {
+		t.Fatalf("expected the address assignment the gateway was placed with")
+	}
+	// the single pool address is enough for the only cluster the gateway is placed on
+	if len(addresses.unassigned) != 0 || addresses.pooled["c1"].Value != "10.0.0.1" {
+		t.Errorf("expected the pool address to be assigned to c1 only but got %v, unassigned %v", addresses.pooled, addresses.unassigned)
+	}
+	condition := buildAddressesAssignedCondition(gateway.Generation, addresses, nil)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Errorf("expected the addresses to be reported as assigned but got %v", condition)
+	}
+}
@@ -0,0 +1,247 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// TLSFingerprintAnnotation holds the SHA-256 fingerprint of the certificate of a placed TLS secret
+	TLSFingerprintAnnotation = LabelPrefix + "tls-fingerprint"
+	// TLSNotAfterAnnotation holds the expiry time of the certificate of a placed TLS secret
+	TLSNotAfterAnnotation = LabelPrefix + "tls-not-after"
+
+	// CertificatesSyncedConditionType reports whether the clusters hold the same listener certificates as the hub
+	CertificatesSyncedConditionType = LabelPrefix + "CertificatesSynced"
+
+	CertificatesSyncedReason   = "CertificatesSynced"
+	CertificatesMismatchReason = "CertificatesMismatch"
+	CertificatesPendingReason  = "CertificatesPending"
+
+	// CertificateExpiringReason is the reason of the warning event emitted when a placed certificate is about to expire
+	CertificateExpiringReason = "CertificateExpiring"
+
+	// DefaultCertificateExpiryWarning is how long before expiry a placed certificate is reported as expiring
+	DefaultCertificateExpiryWarning = 14 * 24 * time.Hour
+)
+
+// placedCertificate is the state of a listener certificate in a cluster the gateway is placed on
+type placedCertificate struct {
+	cluster string
+	secret  string
+	// reported is false until the cluster feeds back the annotations of the secret
+	reported bool
+	mismatch bool
+	notAfter time.Time
+}
+
+// parseTLSCertificate returns the leaf certificate of a kubernetes.io/tls secret
+func parseTLSCertificate(secret *corev1.Secret) (*x509.Certificate, error) {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate found in secret %s/%s", secret.Namespace, secret.Name)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func certificateFingerprint(certificate *x509.Certificate) string {
+	sum := sha256.Sum256(certificate.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// addCertificateAnnotations annotates a downstream TLS secret with the fingerprint and expiry of its certificate
+// so that the certificate applied in each cluster can be compared with the hub from the status feedback
+func addCertificateAnnotations(secret *corev1.Secret) error {
+	certificate, err := parseTLSCertificate(secret)
+	if err != nil {
+		return err
+	}
+	annotations := map[string]string{}
+	for key, value := range secret.Annotations {
+		annotations[key] = value
+	}
+	annotations[TLSFingerprintAnnotation] = certificateFingerprint(certificate)
+	annotations[TLSNotAfterAnnotation] = certificate.NotAfter.UTC().Format(time.RFC3339)
+	secret.Annotations = annotations
+	return nil
+}
+
+// placedCertificates compares the certificates of the secrets referenced by the valid listeners of the gateway
+// with the certificates applied in each of the clusters
+func (r *GatewayReconciler) placedCertificates(ctx context.Context, gateway *gatewayapiv1.Gateway, clusters []string, invalidListeners map[gatewayapiv1.SectionName]metav1.Condition) ([]placedCertificate, error) {
+	log := crlog.FromContext(ctx)
+	fingerprints := map[string]string{}
+	for _, listener := range gateway.Spec.Listeners {
+		if _, invalid := invalidListeners[listener.Name]; invalid || listener.TLS == nil {
+			continue
+		}
+		for _, secretRef := range listener.TLS.CertificateRefs {
+			ns := gateway.Namespace
+			if secretRef.Namespace != nil {
+				ns = string(*secretRef.Namespace)
+			}
+			secret := &corev1.Secret{}
+			if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ns, Name: string(secretRef.Name)}, secret); err != nil {
+				if !k8serrors.IsNotFound(err) {
+					return nil, err
+				}
+				continue
+			}
+			certificate, err := parseTLSCertificate(secret)
+			if err != nil {
+				continue
+			}
+			fingerprints[secret.Name] = certificateFingerprint(certificate)
+		}
+	}
+	if len(fingerprints) == 0 {
+		return nil, nil
+	}
+
+	certificates := []placedCertificate{}
+	for _, cluster := range clusters {
+		secretAnnotations, err := r.Placement.GetSecretAnnotations(ctx, gateway, cluster)
+		if err != nil {
+			// May not have the status yet, the certificates are reported as pending
+			log.V(3).Info("secret annotations unknown for cluster", "cluster", cluster, "message", err)
+		}
+		for secret, fingerprint := range fingerprints {
+			certificate := placedCertificate{cluster: cluster, secret: secret}
+			annotations, reported := secretAnnotations[secret]
+			if reported && annotations[TLSFingerprintAnnotation] != "" {
+				certificate.reported = true
+				certificate.mismatch = annotations[TLSFingerprintAnnotation] != fingerprint
+				if notAfter, err := time.Parse(time.RFC3339, annotations[TLSNotAfterAnnotation]); err == nil {
+					certificate.notAfter = notAfter
+				}
+			}
+			certificates = append(certificates, certificate)
+		}
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		if certificates[i].cluster != certificates[j].cluster {
+			return certificates[i].cluster < certificates[j].cluster
+		}
+		return certificates[i].secret < certificates[j].secret
+	})
+	return certificates, nil
+}
+
+// buildCertificatesSyncedCondition reports the clusters holding a certificate that differs from the hub.
+// nil is returned when none of the placed listeners reference a TLS secret
+func buildCertificatesSyncedCondition(generation int64, certificates []placedCertificate) *metav1.Condition {
+	if len(certificates) == 0 {
+		return nil
+	}
+	mismatched := []string{}
+	pending := []string{}
+	for _, certificate := range certificates {
+		if !certificate.reported {
+			pending = append(pending, fmt.Sprintf("%s %s", certificate.cluster, certificate.secret))
+			continue
+		}
+		if certificate.mismatch {
+			mismatched = append(mismatched, fmt.Sprintf("%s %s", certificate.cluster, certificate.secret))
+		}
+	}
+	condition := &metav1.Condition{
+		Type:               CertificatesSyncedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             CertificatesSyncedReason,
+		Message:            "certificates in all clusters match the hub",
+		ObservedGeneration: generation,
+	}
+	if len(mismatched) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = CertificatesMismatchReason
+		condition.Message = fmt.Sprintf("certificates do not match the hub: %s", strings.Join(mismatched, "; "))
+	} else if len(pending) > 0 {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = CertificatesPendingReason
+		condition.Message = fmt.Sprintf("certificates not yet reported: %s", strings.Join(pending, "; "))
+	}
+	return condition
+}
+
+// expiringCertificates returns the certificates applied in the clusters that expire within the warning window
+func expiringCertificates(certificates []placedCertificate, now time.Time, window time.Duration) []placedCertificate {
+	expiring := []placedCertificate{}
+	for _, certificate := range certificates {
+		if certificate.reported && !certificate.notAfter.IsZero() && certificate.notAfter.Before(now.Add(window)) {
+			expiring = append(expiring, certificate)
+		}
+	}
+	return expiring
+}
+
+// expiryWarnings remembers the certificates of each gateway a warning event was emitted for, so that the event is
+// emitted once when the certificate enters the warning window rather than on every reconcile
+type expiryWarnings struct {
+	mu     sync.Mutex
+	warned map[types.NamespacedName]sets.Set[string]
+}
+
+// entered returns the expiring certificates of the gateway that were not expiring on the previous call. A renewed
+// certificate has a new expiry and is warned about again when it enters the window
+func (w *expiryWarnings) entered(gateway types.NamespacedName, expiring []placedCertificate) []placedCertificate {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.warned == nil {
+		w.warned = map[types.NamespacedName]sets.Set[string]{}
+	}
+	previous := w.warned[gateway]
+	current := sets.New[string]()
+	entered := []placedCertificate{}
+	for _, certificate := range expiring {
+		key := fmt.Sprintf("%s/%s/%s", certificate.cluster, certificate.secret, certificate.notAfter.UTC().Format(time.RFC3339))
+		current.Insert(key)
+		if !previous.Has(key) {
+			entered = append(entered, certificate)
+		}
+	}
+	if current.Len() == 0 {
+		delete(w.warned, gateway)
+	} else {
+		w.warned[gateway] = current
+	}
+	return entered
+}
+
+// forget drops the warnings of a deleted gateway
+func (w *expiryWarnings) forget(gateway types.NamespacedName) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.warned, gateway)
+}
+
+// nextExpiryWarning returns how long until the next certificate enters the warning window, 0 when there is none
+func nextExpiryWarning(certificates []placedCertificate, now time.Time, window time.Duration) time.Duration {
+	var next time.Duration
+	for _, certificate := range certificates {
+		if !certificate.reported || certificate.notAfter.IsZero() {
+			continue
+		}
+		until := certificate.notAfter.Add(-window).Sub(now)
+		if until > 0 && (next == 0 || until < next) {
+			next = until
+		}
+	}
+	return next
+}
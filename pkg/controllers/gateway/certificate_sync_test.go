@@ -0,0 +1,173 @@
+//go:build unit
+
+package gateway
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestAddCertificateAnnotations(t *testing.T) {
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	certPEM, keyPEM := testutil.GenerateTLSCertificate("test.example.com", notAfter)
+	upstreamAnnotations := map[string]string{"test": "test"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testutil.TLSSecretName,
+			Namespace:   testutil.Namespace,
+			Annotations: upstreamAnnotations,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+	if err := addCertificateAnnotations(secret); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	certificate, err := parseTLSCertificate(secret)
+	if err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	if secret.Annotations[TLSFingerprintAnnotation] != certificateFingerprint(certificate) {
+		t.Errorf("expected fingerprint %s but got %s", certificateFingerprint(certificate), secret.Annotations[TLSFingerprintAnnotation])
+	}
+	if secret.Annotations[TLSNotAfterAnnotation] != "2030-01-01T00:00:00Z" {
+		t.Errorf("expected expiry 2030-01-01T00:00:00Z but got %s", secret.Annotations[TLSNotAfterAnnotation])
+	}
+	if secret.Annotations["test"] != "test" {
+		t.Errorf("expected existing annotations to be kept but got %v", secret.Annotations)
+	}
+	if _, ok := upstreamAnnotations[TLSFingerprintAnnotation]; ok {
+		t.Errorf("expected the annotations of the upstream secret to be left unchanged")
+	}
+
+	secret.Data[corev1.TLSCertKey] = []byte("invalid")
+	if err := addCertificateAnnotations(secret); err == nil {
+		t.Errorf("expected an error for an invalid certificate but got none")
+	}
+}
+
+func TestBuildCertificatesSyncedCondition(t *testing.T) {
+	testCases := []struct {
+		name         string
+		certificates []placedCertificate
+		wantNil      bool
+		wantStatus   metav1.ConditionStatus
+		wantReason   string
+		wantMessage  string
+	}{
+		{
+			name:    "no condition without certificates",
+			wantNil: true,
+		},
+		{
+			name: "synced when all clusters match the hub",
+			certificates: []placedCertificate{
+				{cluster: "a", secret: "tls", reported: true},
+				{cluster: "b", secret: "tls", reported: true},
+			},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  CertificatesSyncedReason,
+			wantMessage: "certificates in all clusters match the hub",
+		},
+		{
+			name: "unknown while a cluster has not reported",
+			certificates: []placedCertificate{
+				{cluster: "a", secret: "tls", reported: true},
+				{cluster: "b", secret: "tls"},
+			},
+			wantStatus:  metav1.ConditionUnknown,
+			wantReason:  CertificatesPendingReason,
+			wantMessage: "certificates not yet reported: b tls",
+		},
+		{
+			name: "not synced when a cluster holds another certificate",
+			certificates: []placedCertificate{
+				{cluster: "a", secret: "tls", reported: true, mismatch: true},
+				{cluster: "b", secret: "tls"},
+			},
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  CertificatesMismatchReason,
+			wantMessage: "certificates do not match the hub: a tls",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			condition := buildCertificatesSyncedCondition(1, testCase.certificates)
+			if testCase.wantNil {
+				if condition != nil {
+					t.Fatalf("expected no condition but got %v", condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("expected a condition but got none")
+			}
+			if condition.Status != testCase.wantStatus || condition.Reason != testCase.wantReason || condition.Message != testCase.wantMessage {
+				t.Errorf("expected %s %s %q but got %s %s %q", testCase.wantStatus, testCase.wantReason, testCase.wantMessage, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
+
+func TestExpiringCertificates(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := 14 * 24 * time.Hour
+	certificates := []placedCertificate{
+		{cluster: "a", secret: "expiring", reported: true, notAfter: now.Add(24 * time.Hour)},
+		{cluster: "a", secret: "valid", reported: true, notAfter: now.Add(20 * 24 * time.Hour)},
+		{cluster: "b", secret: "unreported"},
+	}
+
+	expiring := expiringCertificates(certificates, now, window)
+	if len(expiring) != 1 || expiring[0].secret != "expiring" {
+		t.Errorf("expected only the expiring certificate but got %v", expiring)
+	}
+	if next := nextExpiryWarning(certificates, now, window); next != 6*24*time.Hour {
+		t.Errorf("expected the next warning in 6 days but got %s", next)
+	}
+	if next := nextExpiryWarning(expiring, now, window); next != 0 {
+		t.Errorf("expected no next warning once all certificates are expiring but got %s", next)
+	}
+}
+
+func TestExpiryWarningsEntered(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	gateway := types.NamespacedName{Namespace: "test", Name: "test"}
+	expiring := placedCertificate{cluster: "a", secret: "expiring", reported: true, notAfter: now.Add(24 * time.Hour)}
+	renewed := placedCertificate{cluster: "a", secret: "expiring", reported: true, notAfter: now.Add(48 * time.Hour)}
+	other := placedCertificate{cluster: "b", secret: "expiring", reported: true, notAfter: now.Add(24 * time.Hour)}
+
+	warnings := expiryWarnings{}
+	steps := []struct {
+		name     string
+		expiring []placedCertificate
+		want     []placedCertificate
+	}{
+		{name: "enters the window", expiring: []placedCertificate{expiring}, want: []placedCertificate{expiring}},
+		{name: "already warned", expiring: []placedCertificate{expiring}, want: []placedCertificate{}},
+		{name: "another cluster enters the window", expiring: []placedCertificate{expiring, other}, want: []placedCertificate{other}},
+		{name: "renewed certificate enters the window again", expiring: []placedCertificate{renewed, other}, want: []placedCertificate{renewed}},
+		{name: "no longer expiring", expiring: []placedCertificate{}, want: []placedCertificate{}},
+		{name: "enters the window after leaving it", expiring: []placedCertificate{other}, want: []placedCertificate{other}},
+	}
+	for _, step := range steps {
+		if got := warnings.entered(gateway, step.expiring); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: expected %v but got %v", step.name, step.want, got)
+		}
+	}
+
+	warnings.forget(gateway)
+	if got := warnings.entered(gateway, []placedCertificate{other}); len(got) != 1 {
+		t.Errorf("expected a forgotten gateway to be warned again but got %v", got)
+	}
+}
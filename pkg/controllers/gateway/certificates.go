@@ -0,0 +1,169 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	certmanv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// CertificatesReadyConditionType reports the state of the cert-manager certificates referenced by the gateway listeners
+	CertificatesReadyConditionType = LabelPrefix + "CertificatesReady"
+
+	CertificatesReadyReason    = "CertificatesReady"
+	CertificatesNotReadyReason = "CertificatesNotReady"
+)
+
+// getSecretCertificate returns the cert-manager Certificate that manages the secret.
+// nil is returned when the secret is not managed by cert-manager or the Certificate no longer exists
+func getSecretCertificate(ctx context.Context, c client.Client, secret *corev1.Secret) (*certmanv1.Certificate, error) {
+	name, ok := secret.Annotations[certmanv1.CertificateNameKey]
+	if !ok {
+		return nil, nil
+	}
+	certificate := &certmanv1.Certificate{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: secret.Namespace, Name: name}, certificate); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return certificate, nil
+}
+
+func getCertificateCondition(certificate *certmanv1.Certificate, conditionType certmanv1.CertificateConditionType) *certmanv1.CertificateCondition {
+	for i := range certificate.Status.Conditions {
+		if certificate.Status.Conditions[i].Type == conditionType {
+			return &certificate.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func isCertificateReady(certificate *certmanv1.Certificate) bool {
+	ready := getCertificateCondition(certificate, certmanv1.CertificateConditionReady)
+	return ready != nil && ready.Status == cmmeta.ConditionTrue && ready.ObservedGeneration == certificate.Generation
+}
+
+// isCertificateIssued checks whether the certificate has been issued at least once, in which case the
+// secret still holds the last issued certificate while a renewal is in progress or failing
+func isCertificateIssued(certificate *certmanv1.Certificate) bool {
+	return certificate.Status.Revision != nil
+}
+
+// gatewayCertificates returns the cert-manager Certificates managing the secrets referenced by the gateway listeners
+func (r *GatewayReconciler) gatewayCertificates(ctx context.Context, gateway *gatewayapiv1.Gateway) ([]*certmanv1.Certificate, error) {
+	certificates := []*certmanv1.Certificate{}
+	seen := map[client.ObjectKey]bool{}
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, secretRef := range listener.TLS.CertificateRefs {
+			ns := gateway.Namespace
+			if secretRef.Namespace != nil {
+				ns = string(*secretRef.Namespace)
+			}
+			secret := &corev1.Secret{}
+			if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ns, Name: string(secretRef.Name)}, secret); err != nil {
+				if client.IgnoreNotFound(err) != nil {
+					return nil, err
+				}
+				continue
+			}
+			certificate, err := getSecretCertificate(ctx, r.Client, secret)
+			if err != nil {
+				return nil, err
+			}
+			if certificate == nil || seen[client.ObjectKeyFromObject(certificate)] {
+				continue
+			}
+			seen[client.ObjectKeyFromObject(certificate)] = true
+			certificates = append(certificates, certificate)
+		}
+	}
+	return certificates, nil
+}
+
+// buildCertificatesReadyCondition reports the expiry and renewal state of the certificates.
+// nil is returned when none of the listener secrets are managed by cert-manager
+func buildCertificatesReadyCondition(generation int64, certificates []*certmanv1.Certificate) *metav1.Condition {
+	if len(certificates) == 0 {
+		return nil
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return client.ObjectKeyFromObject(certificates[i]).String() < client.ObjectKeyFromObject(certificates[j]).String()
+	})
+
+	status := metav1.ConditionTrue
+	messages := []string{}
+	for _, certificate := range certificates {
+		message := client.ObjectKeyFromObject(certificate).String()
+		if !isCertificateReady(certificate) {
+			status = metav1.ConditionFalse
+			message += " not ready"
+			if ready := getCertificateCondition(certificate, certmanv1.CertificateConditionReady); ready != nil && ready.Message != "" {
+				message += ": " + ready.Message
+			}
+		}
+		if certificate.Status.NotAfter != nil {
+			message += fmt.Sprintf(" expires %s", certificate.Status.NotAfter.UTC().Format(time.RFC3339))
+		}
+		if certificate.Status.RenewalTime != nil {
+			message += fmt.Sprintf(" renews %s", certificate.Status.RenewalTime.UTC().Format(time.RFC3339))
+		}
+		if certificate.Status.LastFailureTime != nil {
+			status = metav1.ConditionFalse
+			message += fmt.Sprintf(" renewal failed %s", certificate.Status.LastFailureTime.UTC().Format(time.RFC3339))
+			if certificate.Status.FailedIssuanceAttempts != nil {
+				message += fmt.Sprintf(" after %d attempts", *certificate.Status.FailedIssuanceAttempts)
+			}
+			if issuing := getCertificateCondition(certificate, certmanv1.CertificateConditionIssuing); issuing != nil && issuing.Message != "" {
+				message += ": " + issuing.Message
+			}
+		}
+		messages = append(messages, message)
+	}
+
+	reason := CertificatesReadyReason
+	if status != metav1.ConditionTrue {
+		reason = CertificatesNotReadyReason
+	}
+	return &metav1.Condition{
+		Type:               CertificatesReadyConditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            strings.Join(messages, "; "),
+		ObservedGeneration: generation,
+	}
+}
+
+// certificateToGateways maps a Certificate to the gateways referencing its secret so that issuance
+// and renewals are propagated as soon as cert-manager updates the Certificate
+func (r *GatewayReconciler) certificateToGateways(ctx context.Context, o client.Object) []reconcile.Request {
+	log := crlog.FromContext(ctx)
+	certificate, ok := o.(*certmanv1.Certificate)
+	if !ok {
+		return nil
+	}
+	gateways, err := listGatewaysForSecret(ctx, r.Client, certificate.Namespace, certificate.Spec.SecretName)
+	if err != nil {
+		log.Error(err, "failed to list gateways for certificate", "certificate", client.ObjectKeyFromObject(certificate))
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, gateway := range gateways {
+		log.V(3).Info("enqueuing gateway based on certificate change", "gateway", gateway.Name, "namespace", gateway.Namespace)
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+	}
+	return requests
+}
@@ -0,0 +1,166 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	certmanv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	fakeplacement "github.com/Kuadrant/multicluster-gateway-controller/pkg/placement/fake"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func buildTestCertificate(ready cmmeta.ConditionStatus, revision *int) *certmanv1.Certificate {
+	notAfter := metav1.NewTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	return &certmanv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testutil.TLSSecretName,
+			Namespace: testutil.Namespace,
+		},
+		Spec: certmanv1.CertificateSpec{
+			SecretName: testutil.TLSSecretName,
+		},
+		Status: certmanv1.CertificateStatus{
+			Conditions: []certmanv1.CertificateCondition{
+				{
+					Type:    certmanv1.CertificateConditionReady,
+					Status:  ready,
+					Message: "issuer not found",
+				},
+			},
+			NotAfter: &notAfter,
+			Revision: revision,
+		},
+	}
+}
+
+func TestBuildCertificatesReadyCondition(t *testing.T) {
+	failed := buildTestCertificate(cmmeta.ConditionTrue, testutil.Pointer(1))
+	failed.Status.LastFailureTime = &metav1.Time{Time: time.Date(2029, 12, 1, 0, 0, 0, 0, time.UTC)}
+	failed.Status.FailedIssuanceAttempts = testutil.Pointer(3)
+
+	testCases := []struct {
+		name         string
+		certificates []*certmanv1.Certificate
+		wantNil      bool
+		wantStatus   metav1.ConditionStatus
+		wantMessage  string
+	}{
+		{
+			name:    "no cert-manager certificates",
+			wantNil: true,
+		},
+		{
+			name:         "ready certificate reports expiry",
+			certificates: []*certmanv1.Certificate{buildTestCertificate(cmmeta.ConditionTrue, testutil.Pointer(1))},
+			wantStatus:   metav1.ConditionTrue,
+			wantMessage:  "expires 2030-01-01T00:00:00Z",
+		},
+		{
+			name:         "not ready certificate",
+			certificates: []*certmanv1.Certificate{buildTestCertificate(cmmeta.ConditionFalse, nil)},
+			wantStatus:   metav1.ConditionFalse,
+			wantMessage:  "not ready: issuer not found",
+		},
+		{
+			name:         "renewal failure",
+			certificates: []*certmanv1.Certificate{failed},
+			wantStatus:   metav1.ConditionFalse,
+			wantMessage:  "renewal failed 2029-12-01T00:00:00Z after 3 attempts",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := buildCertificatesReadyCondition(1, testCase.certificates)
+			if testCase.wantNil {
+				if got != nil {
+					t.Fatalf("expected no condition but got %v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("expected a condition but got none")
+			}
+			if got.Status != testCase.wantStatus || !strings.Contains(got.Message, testCase.wantMessage) {
+				t.Errorf("buildCertificatesReadyCondition() = %v, want status %v and message containing %q", got, testCase.wantStatus, testCase.wantMessage)
+			}
+		})
+	}
+}
+
+func TestGetTLSSecretsWaitsForCertificate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		certificate *certmanv1.Certificate
+		wantInvalid bool
+	}{
+		{
+			name:        "certificate ready",
+			certificate: buildTestCertificate(cmmeta.ConditionTrue, testutil.Pointer(1)),
+		},
+		{
+			name:        "certificate not yet issued",
+			certificate: buildTestCertificate(cmmeta.ConditionFalse, nil),
+			wantInvalid: true,
+		},
+		{
+			name:        "certificate renewal in progress keeps the issued certificate",
+			certificate: buildTestCertificate(cmmeta.ConditionFalse, testutil.Pointer(1)),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			secrets := getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace)
+			secrets.Items[0].Annotations = map[string]string{certmanv1.CertificateNameKey: testCase.certificate.Name}
+			r := &GatewayReconciler{
+				Client: testutil.GetValidTestClient(secrets, &certmanv1.CertificateList{
+					Items: []certmanv1.Certificate{*testCase.certificate},
+				}),
+				Scheme:    testutil.GetValidTestScheme(),
+				Placement: fakeplacement.NewTestGatewayPlacer(),
+			}
+			upstream := &gatewayapiv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+				Spec: gatewayapiv1.GatewaySpec{
+					Listeners: []gatewayapiv1.Listener{
+						{
+							Name:     testutil.ValidTestHostname,
+							Protocol: gatewayapiv1.HTTPSProtocolType,
+							TLS: &gatewayapiv1.GatewayTLSConfig{
+								CertificateRefs: []gatewayapiv1.SecretObjectReference{
+									{Name: testutil.TLSSecretName},
+								},
+							},
+						},
+					},
+				},
+			}
+			downstream := upstream.DeepCopy()
+			downstream.Namespace = "kuadrant-" + testutil.Namespace
+
+			secretsGot, invalidListeners, err := r.getTLSSecrets(context.TODO(), upstream, downstream, nil)
+			if err != nil {
+				t.Fatalf("did not expect an error but got %s", err)
+			}
+			_, invalid := invalidListeners[testutil.ValidTestHostname]
+			if invalid != testCase.wantInvalid {
+				t.Errorf("expected listener invalid %v, got %v", testCase.wantInvalid, invalidListeners)
+			}
+			if !testCase.wantInvalid && (len(secretsGot) != 1 || secretsGot[0].(*corev1.Secret).Namespace != downstream.Namespace) {
+				t.Errorf("expected the secret to be copied to the downstream namespace, got %v", secretsGot)
+			}
+		})
+	}
+}
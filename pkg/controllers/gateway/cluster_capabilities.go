@@ -0,0 +1,171 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/capabilities"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/envelope"
+)
+
+const (
+	// ClusterCapabilitiesConditionType reports whether the clusters targeted by the gateway have the downstream class,
+	// features and Gateway API version it requires
+	ClusterCapabilitiesConditionType = LabelPrefix + "ClusterCapabilities"
+
+	ClusterCapabilitiesSatisfiedReason   = "CapabilitiesSatisfied"
+	ClusterCapabilitiesMissingReason     = "CapabilitiesMissing"
+	ClusterCapabilitiesNotReportedReason = "CapabilitiesNotReported"
+)
+
+// MissingCapabilities returns the requirements of the params the cluster lacks to run the gateway. reported is false
+// when the cluster, which may be nil when its ManagedCluster is not found, doesn't report its capabilities
+func (p *Params) MissingCapabilities(cluster *clusterv1.ManagedCluster) (missing []string, reported bool) {
+	if p == nil || cluster == nil {
+		return nil, false
+	}
+	discovered := capabilities.FromClusterClaims(cluster.Status.ClusterClaims)
+	if discovered == nil {
+		return nil, false
+	}
+	return discovered.Missing(capabilities.Requirements{
+		GatewayClass:         p.GetDownstreamClassFor(cluster),
+		Features:             p.RequiredFeatures,
+		MinGatewayAPIVersion: p.MinGatewayAPIVersion,
+	}), true
+}
+
+func (p *Params) validateRequirements() error {
+	for _, feature := range p.RequiredFeatures {
+		if errs := validation.IsDNS1123Subdomain(feature); len(errs) > 0 {
+			return fmt.Errorf("invalid requiredFeatures %s: %s", feature, strings.Join(errs, ", "))
+		}
+	}
+	if p.MinGatewayAPIVersion != "" {
+		if err := capabilities.ValidateVersion(p.MinGatewayAPIVersion); err != nil {
+			return fmt.Errorf("invalid minGatewayAPIVersion %s: %v", p.MinGatewayAPIVersion, err)
+		}
+	}
+	return nil
+}
+
+// clusterCapabilities is the result of checking the capabilities of a cluster targeted by the gateway
+type clusterCapabilities struct {
+	cluster string
+	// reported is false when the cluster doesn't report its capabilities and the gateway is placed on it regardless
+	reported bool
+	missing  []string
+}
+
+// targetedClusterCapabilities checks the capabilities of each of the clusters against the params. When requireKey is
+// set the clusters without an encryption key are missing the key claim
+func (r *GatewayReconciler) targetedClusterCapabilities(ctx context.Context, clusters []string, params *Params, requireKey bool) ([]clusterCapabilities, error) {
+	targeted := []clusterCapabilities{}
+	for _, cluster := range clusters {
+		managedCluster := &clusterv1.ManagedCluster{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: cluster}, managedCluster); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			managedCluster = nil
+		}
+		missing, reported := params.MissingCapabilities(managedCluster)
+		if requireKey && !hasPublicKey(managedCluster) {
+			missing = append(missing, envelope.PublicKeyClaim)
+			reported = true
+		}
+		targeted = append(targeted, clusterCapabilities{cluster: cluster, reported: reported, missing: missing})
+	}
+	return targeted, nil
+}
+
+// assignableClusters returns the targeted clusters the gateway is not refused from
+func (r *GatewayReconciler) assignableClusters(ctx context.Context, gateway *gatewayapiv1.Gateway, params *Params, requireKey bool) ([]string, error) {
+	targets, err := r.Placement.GetClusters(ctx, gateway)
+	if err != nil {
+		return nil, err
+	}
+	targeted, err := r.targetedClusterCapabilities(ctx, sets.List(targets), params, requireKey)
+	if err != nil {
+		return nil, err
+	}
+	return sets.List(sets.New[string]().Union(targets).Delete(refusedClusters(targeted)...)), nil
+}
+
+// hasPublicKey returns whether the cluster published a valid encryption key for the placed secrets
+func hasPublicKey(cluster *clusterv1.ManagedCluster) bool {
+	if cluster == nil {
+		return false
+	}
+	_, err := envelope.ClusterPublicKey(cluster)
+	return err == nil
+}
+
+// placesSecrets returns whether the gateway places the TLS secrets of any of its valid listeners
+func placesSecrets(gateway *gatewayapiv1.Gateway, invalidListeners map[gatewayapiv1.SectionName]metav1.Condition) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if _, invalid := invalidListeners[listener.Name]; invalid || listener.TLS == nil {
+			continue
+		}
+		if len(listener.TLS.CertificateRefs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// refusedClusters returns the clusters the gateway is not placed on because they lack some capabilities
+func refusedClusters(targeted []clusterCapabilities) []string {
+	refused := []string{}
+	for _, cluster := range targeted {
+		if len(cluster.missing) > 0 {
+			refused = append(refused, cluster.cluster)
+		}
+	}
+	return refused
+}
+
+// buildClusterCapabilitiesCondition reports the capabilities each targeted cluster is missing. nil is returned when
+// the gateway doesn't target any cluster
+func buildClusterCapabilitiesCondition(generation int64, targeted []clusterCapabilities) *metav1.Condition {
+	if len(targeted) == 0 {
+		return nil
+	}
+	missing := []string{}
+	notReported := []string{}
+	for _, cluster := range targeted {
+		if !cluster.reported {
+			notReported = append(notReported, cluster.cluster)
+			continue
+		}
+		if len(cluster.missing) > 0 {
+			missing = append(missing, fmt.Sprintf("%s (%s)", cluster.cluster, strings.Join(cluster.missing, ", ")))
+		}
+	}
+	condition := &metav1.Condition{
+		Type:               ClusterCapabilitiesConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             ClusterCapabilitiesSatisfiedReason,
+		Message:            "all targeted clusters are able to run the gateway",
+		ObservedGeneration: generation,
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ClusterCapabilitiesMissingReason
+		condition.Message = fmt.Sprintf("gateway not placed on clusters missing capabilities: %s", strings.Join(missing, "; "))
+	} else if len(notReported) > 0 {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = ClusterCapabilitiesNotReportedReason
+		condition.Message = fmt.Sprintf("clusters not reporting their capabilities: %s", strings.Join(notReported, "; "))
+	}
+	return condition
+}
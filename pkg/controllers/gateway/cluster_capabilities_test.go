@@ -0,0 +1,323 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/capabilities"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/envelope"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func reportingCluster(name string, claims map[string]string) *clusterv1.ManagedCluster {
+	cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	cluster.Status.ClusterClaims = append(cluster.Status.ClusterClaims, clusterv1.ManagedClusterClaim{Name: capabilities.ReportedClaim, Value: capabilities.Version})
+	for claim, value := range claims {
+		cluster.Status.ClusterClaims = append(cluster.Status.ClusterClaims, clusterv1.ManagedClusterClaim{Name: claim, Value: value})
+	}
+	return cluster
+}
+
+func TestMissingCapabilities(t *testing.T) {
+	params := &Params{
+		DownstreamClass:      "istio",
+		RequiredFeatures:     []string{"authpolicies.kuadrant.io"},
+		MinGatewayAPIVersion: "v1.0.0",
+	}
+	cases := []struct {
+		name             string
+		params           *Params
+		cluster          *clusterv1.ManagedCluster
+		expectedMissing  []string
+		expectedReported bool
+	}{
+		{
+			name:   "cluster not found",
+			params: params,
+		},
+		{
+			name:    "capabilities not reported",
+			params:  params,
+			cluster: &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c1"}},
+		},
+		{
+			name:   "capabilities satisfied",
+			params: params,
+			cluster: reportingCluster("c1", map[string]string{
+				capabilities.GatewayClassesClaim:    "eg,istio",
+				capabilities.FeaturesClaim:          "authpolicies.kuadrant.io",
+				capabilities.GatewayAPIVersionClaim: "v1.0.0",
+			}),
+			expectedMissing:  []string{},
+			expectedReported: true,
+		},
+		{
+			name:   "capabilities missing",
+			params: params,
+			cluster: reportingCluster("c1", map[string]string{
+				capabilities.GatewayClassesClaim: "eg",
+			}),
+			expectedMissing:  []string{"gateway class istio", "feature authpolicies.kuadrant.io", "Gateway API v1.0.0 or later (found none)"},
+			expectedReported: true,
+		},
+		{
+			name: "class selected for the cluster",
+			params: &Params{
+				DownstreamClass:   "istio",
+				DownstreamClasses: []v1alpha1.ClusterDownstreamClass{{DownstreamClass: "eg", ClusterClaims: map[string]string{capabilities.GatewayClassesClaim: "eg"}}},
+			},
+			cluster:          reportingCluster("c1", map[string]string{capabilities.GatewayClassesClaim: "eg"}),
+			expectedMissing:  []string{},
+			expectedReported: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			missing, reported := testCase.params.MissingCapabilities(testCase.cluster)
+			if reported != testCase.expectedReported {
+				t.Errorf("expected reported %v but got %v", testCase.expectedReported, reported)
+			}
+			if !reflect.DeepEqual(missing, testCase.expectedMissing) {
+				t.Errorf("expected missing %v but got %v", testCase.expectedMissing, missing)
+			}
+		})
+	}
+}
+
+func TestValidateRequirements(t *testing.T) {
+	cases := []struct {
+		name        string
+		params      *Params
+		expectError bool
+	}{
+		{
+			name:   "valid",
+			params: &Params{RequiredFeatures: []string{"authpolicies.kuadrant.io"}, MinGatewayAPIVersion: "v1.0.0"},
+		},
+		{
+			name:        "invalid feature",
+			params:      &Params{RequiredFeatures: []string{"AuthPolicy"}},
+			expectError: true,
+		},
+		{
+			name:        "invalid version",
+			params:      &Params{MinGatewayAPIVersion: "latest"},
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.params.validateRequirements()
+			if (err != nil) != testCase.expectError {
+				t.Errorf("expected error %v but got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
+
+func TestBuildClusterCapabilitiesCondition(t *testing.T) {
+	cases := []struct {
+		name            string
+		targeted        []clusterCapabilities
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "no targeted clusters",
+		},
+		{
+			name: "satisfied",
+			targeted: []clusterCapabilities{
+				{cluster: "c1", reported: true},
+				{cluster: "c2", reported: true},
+			},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  ClusterCapabilitiesSatisfiedReason,
+			expectedMessage: "all targeted clusters are able to run the gateway",
+		},
+		{
+			name: "missing",
+			targeted: []clusterCapabilities{
+				{cluster: "c1", reported: true, missing: []string{"gateway class eg", "feature authpolicies.kuadrant.io"}},
+				{cluster: "c2"},
+				{cluster: "c3", reported: true, missing: []string{"gateway class eg"}},
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  ClusterCapabilitiesMissingReason,
+			expectedMessage: "gateway not placed on clusters missing capabilities: c1 (gateway class eg, feature authpolicies.kuadrant.io); c3 (gateway class eg)",
+		},
+		{
+			name: "not reported",
+			targeted: []clusterCapabilities{
+				{cluster: "c1", reported: true},
+				{cluster: "c2"},
+			},
+			expectedStatus:  metav1.ConditionUnknown,
+			expectedReason:  ClusterCapabilitiesNotReportedReason,
+			expectedMessage: "clusters not reporting their capabilities: c2",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			condition := buildClusterCapabilitiesCondition(1, testCase.targeted)
+			if testCase.expectedStatus == "" {
+				if condition != nil {
+					t.Errorf("expected no condition but got %v", condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("expected a condition but got nil")
+			}
+			if condition.Status != testCase.expectedStatus || condition.Reason != testCase.expectedReason || condition.Message != testCase.expectedMessage {
+				t.Errorf("expected %s %s %q but got %s %s %q", testCase.expectedStatus, testCase.expectedReason, testCase.expectedMessage, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
+
+func TestClusterCustomiserRefusesClusters(t *testing.T) {
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				reportingCluster("c1", map[string]string{capabilities.GatewayClassesClaim: "istio"}),
+				reportingCluster("c2", map[string]string{capabilities.GatewayClassesClaim: "eg"}),
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c3"}},
+			).
+			Build(),
+	}
+
+	customise := r.clusterCustomiser(&Params{DownstreamClass: "istio"}, nil, nil)
+	expectedRefused := map[string]bool{"c1": false, "c2": true, "c3": false, "c4": false}
+	for cluster, refused := range expectedRefused {
+		gateway := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+		err := customise(context.TODO(), cluster, gateway)
+		if errors.Is(err, placement.ErrClusterRefused) != refused {
+			t.Errorf("expected cluster %s refused %v but got %v", cluster, refused, err)
+		}
+		if !refused && err != nil {
+			t.Errorf("did not expect an error for cluster %s but got %v", cluster, err)
+		}
+	}
+}
+
+func TestTargetedClusterCapabilitiesRequireKey(t *testing.T) {
+	key, err := envelope.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				reportingCluster("c1", map[string]string{
+					capabilities.GatewayClassesClaim: "istio",
+					envelope.PublicKeyClaim:          envelope.EncodePublicKey(key.PublicKey()),
+				}),
+				reportingCluster("c2", map[string]string{capabilities.GatewayClassesClaim: "istio"}),
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c3"}},
+			).
+			Build(),
+	}
+
+	testCases := []struct {
+		name       string
+		requireKey bool
+		expected   []clusterCapabilities
+	}{
+		{
+			name:       "key not required",
+			requireKey: false,
+			expected: []clusterCapabilities{
+				{cluster: "c1", reported: true},
+				{cluster: "c2", reported: true},
+				{cluster: "c3", reported: false},
+				{cluster: "c4", reported: false},
+			},
+		},
+		{
+			name:       "clusters without key are missing the key claim",
+			requireKey: true,
+			expected: []clusterCapabilities{
+				{cluster: "c1", reported: true},
+				{cluster: "c2", reported: true, missing: []string{envelope.PublicKeyClaim}},
+				{cluster: "c3", reported: true, missing: []string{envelope.PublicKeyClaim}},
+				{cluster: "c4", reported: true, missing: []string{envelope.PublicKeyClaim}},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			targeted, err := r.targetedClusterCapabilities(context.TODO(), []string{"c1", "c2", "c3", "c4"}, &Params{DownstreamClass: "istio"}, testCase.requireKey)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i := range targeted {
+				if len(targeted[i].missing) == 0 {
+					targeted[i].missing = nil
+				}
+			}
+			if !reflect.DeepEqual(targeted, testCase.expected) {
+				t.Errorf("expected %+v but got %+v", testCase.expected, targeted)
+			}
+		})
+	}
+}
+
+func TestPlacesSecrets(t *testing.T) {
+	tlsListener := gatewayapiv1.Listener{
+		Name: "tls",
+		TLS:  &gatewayapiv1.GatewayTLSConfig{CertificateRefs: []gatewayapiv1.SecretObjectReference{{Name: "cert"}}},
+	}
+	testCases := []struct {
+		name     string
+		gateway  *gatewayapiv1.Gateway
+		invalid  map[gatewayapiv1.SectionName]metav1.Condition
+		expected bool
+	}{
+		{
+			name:     "no tls listener",
+			gateway:  &gatewayapiv1.Gateway{Spec: gatewayapiv1.GatewaySpec{Listeners: []gatewayapiv1.Listener{{Name: "http"}}}},
+			expected: false,
+		},
+		{
+			name:     "valid tls listener",
+			gateway:  &gatewayapiv1.Gateway{Spec: gatewayapiv1.GatewaySpec{Listeners: []gatewayapiv1.Listener{tlsListener}}},
+			expected: true,
+		},
+		{
+			name:     "invalid tls listener",
+			gateway:  &gatewayapiv1.Gateway{Spec: gatewayapiv1.GatewaySpec{Listeners: []gatewayapiv1.Listener{tlsListener}}},
+			invalid:  map[gatewayapiv1.SectionName]metav1.Condition{"tls": {}},
+			expected: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := placesSecrets(testCase.gateway, testCase.invalid); got != testCase.expected {
+				t.Errorf("expected %v but got %v", testCase.expected, got)
+			}
+		})
+	}
+}
@@ -14,10 +14,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
-	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/clusterSecret"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
 )
 
+// GatewaySecretIndex indexes gateways by the namespace/name keys of the secrets referenced by their TLS listeners
+const GatewaySecretIndex = "spec.listeners.tls.certificateRefs"
+
 type ClusterEventHandler struct {
 	client client.Client
 }
@@ -44,14 +46,17 @@ func (eh *ClusterEventHandler) Update(ctx context.Context, e event.UpdateEvent,
 	eh.enqueueForObject(ctx, e.ObjectNew, q)
 }
 
+// enqueueForObject enqueues the gateways that reference the secret from a listener so that listeners
+// with a missing or invalid certificate are reconciled again once the secret is fixed
 func (eh *ClusterEventHandler) enqueueForObject(ctx context.Context, obj v1.Object, q workqueue.RateLimitingInterface) {
-	if !clusterSecret.IsClusterSecret(obj) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
 		return
 	}
 
-	gateways, err := eh.getGatewaysFor(ctx, obj.(*corev1.Secret))
+	gateways, err := eh.getGatewaysFor(ctx, secret)
 	if err != nil {
-		log.Log.Error(err, "failed to get gateways when enqueueing from cluster secret")
+		log.Log.Error(err, "failed to get gateways when enqueueing from secret")
 		return
 	}
 
@@ -64,25 +69,40 @@ func (eh *ClusterEventHandler) enqueueForObject(ctx context.Context, obj v1.Obje
 }
 
 func (eh *ClusterEventHandler) getGatewaysFor(ctx context.Context, secret *corev1.Secret) ([]gatewayapiv1.Gateway, error) {
+	return listGatewaysForSecret(ctx, eh.client, secret.Namespace, secret.Name)
+}
 
+// listGatewaysForSecret lists the gateways in any namespace with a listener referencing the secret
+func listGatewaysForSecret(ctx context.Context, c client.Client, namespace, name string) ([]gatewayapiv1.Gateway, error) {
 	gateways := &gatewayapiv1.GatewayList{}
-	if err := eh.client.List(ctx, gateways, &client.ListOptions{Namespace: secret.Namespace}); err != nil {
+	if err := c.List(ctx, gateways, client.MatchingFields{GatewaySecretIndex: fmt.Sprintf("%s/%s", namespace, name)}); err != nil {
 		return nil, err
 	}
+	return gateways.Items, nil
+}
 
-	return slice.Filter(gateways.Items, func(gateway gatewayapiv1.Gateway) bool {
-		for _, l := range gateway.Spec.Listeners {
-			if l.Protocol != gatewayapiv1.HTTPSProtocolType || l.TLS == nil {
-				continue
+// gatewaySecretKeys is the GatewaySecretIndex extractor. It returns the namespace/name keys of the secrets referenced
+// by the TLS listeners of the gateway, defaulting the namespace of a reference to the namespace of the gateway
+func gatewaySecretKeys(o client.Object) []string {
+	gateway, ok := o.(*gatewayapiv1.Gateway)
+	if !ok {
+		return nil
+	}
+	keys := []string{}
+	for _, l := range gateway.Spec.Listeners {
+		if l.TLS == nil {
+			continue
+		}
+		for _, ts := range l.TLS.CertificateRefs {
+			ns := gateway.Namespace
+			if ts.Namespace != nil {
+				ns = string(*ts.Namespace)
 			}
-
-			for _, ts := range l.TLS.CertificateRefs {
-				if ts.Name == gatewayapiv1.ObjectName(secret.Name) && *ts.Namespace == gatewayapiv1.Namespace(secret.Namespace) {
-					return true
-				}
+			key := fmt.Sprintf("%s/%s", ns, ts.Name)
+			if !slice.ContainsString(keys, key) {
+				keys = append(keys, key)
 			}
-
 		}
-		return false
-	}), nil
+	}
+	return keys
 }
@@ -101,7 +101,7 @@ func TestClusterEventHandler(t *testing.T) {
 			},
 		},
 		{
-			name:     "Not enqueued. Not a cluster secret",
+			name:     "Not enqueued. Secret not referenced by a listener",
 			scheme:   testutil.GetValidTestScheme(),
 			gateways: testGateway(),
 			secret: corev1.Secret{
@@ -112,6 +112,137 @@ func TestClusterEventHandler(t *testing.T) {
 			},
 			enqueuedGateways: make([]gatewayapiv1.Gateway, 0),
 		},
+		{
+			name:   "Queued one. TLS secret in the gateway namespace",
+			scheme: testutil.GetValidTestScheme(),
+			gateways: []gatewayapiv1.Gateway{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      testutil.MultiClusterGatewayClassName,
+						Namespace: testutil.Namespace,
+					},
+					Spec: gatewayapiv1.GatewaySpec{
+						Listeners: []gatewayapiv1.Listener{
+							{
+								Hostname: testutil.Pointer(gatewayapiv1.Hostname(testutil.ValidTestHostname)),
+								Protocol: gatewayapiv1.HTTPSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Name: gatewayapiv1.ObjectName(testutil.TLSSecretName),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.TLSSecretName,
+					Namespace: testutil.Namespace,
+				},
+				Type: corev1.SecretTypeTLS,
+			},
+			enqueuedGateways: []gatewayapiv1.Gateway{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      testutil.MultiClusterGatewayClassName,
+						Namespace: testutil.Namespace,
+					},
+				},
+			},
+		},
+		{
+			name:   "Queued all. TLS terminate and cross namespace references",
+			scheme: testutil.GetValidTestScheme(),
+			gateways: []gatewayapiv1.Gateway{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "tls-terminate",
+						Namespace: testutil.Namespace,
+					},
+					Spec: gatewayapiv1.GatewaySpec{
+						Listeners: []gatewayapiv1.Listener{
+							{
+								Protocol: gatewayapiv1.TLSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									Mode: testutil.Pointer(gatewayapiv1.TLSModeTerminate),
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Name: gatewayapiv1.ObjectName(testutil.TLSSecretName),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cross-namespace",
+						Namespace: "other",
+					},
+					Spec: gatewayapiv1.GatewaySpec{
+						Listeners: []gatewayapiv1.Listener{
+							{
+								Protocol: gatewayapiv1.HTTPSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Name:      gatewayapiv1.ObjectName(testutil.TLSSecretName),
+											Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "same-name-other-namespace",
+						Namespace: "other",
+					},
+					Spec: gatewayapiv1.GatewaySpec{
+						Listeners: []gatewayapiv1.Listener{
+							{
+								Protocol: gatewayapiv1.HTTPSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Name: gatewayapiv1.ObjectName(testutil.TLSSecretName),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.TLSSecretName,
+					Namespace: testutil.Namespace,
+				},
+				Type: corev1.SecretTypeTLS,
+			},
+			enqueuedGateways: []gatewayapiv1.Gateway{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "tls-terminate",
+						Namespace: testutil.Namespace,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cross-namespace",
+						Namespace: "other",
+					},
+				},
+			},
+		},
 		{
 			name:     "Not enqueued. Error parsing cluster config",
 			scheme:   testutil.GetValidTestScheme(),
@@ -160,11 +291,15 @@ func TestClusterEventHandler(t *testing.T) {
 
 	for _, testCase := range cases {
 		t.Run(testCase.name, func(t *testing.T) {
-			client := fake.NewClientBuilder().WithScheme(testCase.scheme).WithLists(
-				&gatewayapiv1.GatewayList{
-					Items: testCase.gateways,
-				},
-			).Build()
+			builder := fake.NewClientBuilder().WithScheme(testCase.scheme)
+			if testCase.scheme.Recognizes(gatewayapiv1.SchemeGroupVersion.WithKind("Gateway")) {
+				builder = builder.WithLists(
+					&gatewayapiv1.GatewayList{
+						Items: testCase.gateways,
+					},
+				).WithIndex(&gatewayapiv1.Gateway{}, GatewaySecretIndex, gatewaySecretKeys)
+			}
+			client := builder.Build()
 
 			testQ := &TestQueue{t: t}
 			clusterEventHandler := &ClusterEventHandler{
@@ -6,14 +6,25 @@ import (
 
 	"github.com/go-logr/logr"
 
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
-	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 )
 
+// GatewayClustersIndex indexes gateways by the clusters they have been placed on
+const GatewayClustersIndex = "metadata.annotations.gateway-clusters"
+
 // ClusterEventMapper is an EventHandler that maps Cluster object events to gateway events.
 //
 // Cluster object can be anything that represents a cluster and has mgc attribute labels applied to (e.g. OCM ManagedCluster)
@@ -45,28 +56,102 @@ func (m *ClusterEventMapper) mapToGatewayRequest(ctx context.Context, obj client
 		return []reconcile.Request{}
 	}
 
-	clusterName := obj.GetName()
-
-	allGwList := &gatewayapiv1.GatewayList{}
-	err := m.Client.List(ctx, allGwList)
-	if err != nil {
+	gateways := &gatewayapiv1.GatewayList{}
+	if err := m.Client.List(ctx, gateways, client.MatchingFields{GatewayClustersIndex: obj.GetName()}); err != nil {
 		logger.Info("mapToGatewayRequest:", "error", "failed to get gateways")
 		return []reconcile.Request{}
 	}
 
-	requests := make([]reconcile.Request, 0)
-	for _, gw := range allGwList.Items {
-		val := metadata.GetAnnotation(&gw, GatewayClustersAnnotation)
-		if val == "" {
+	// the gateways targeting the cluster are mapped as well so that a cluster refused by a gateway, for example
+	// until it publishes its capabilities or encryption key, is placed once it does
+	targeting, err := m.targetingGateways(ctx, obj.GetName())
+	if err != nil {
+		logger.Info("mapToGatewayRequest:", "error", "failed to get gateways targeting the cluster")
+	}
+	gateways.Items = append(gateways.Items, targeting...)
+
+	requests := make([]reconcile.Request, 0, len(gateways.Items))
+	seen := map[client.ObjectKey]bool{}
+	for _, gw := range gateways.Items {
+		key := client.ObjectKeyFromObject(&gw)
+		if seen[key] {
 			continue
 		}
-		var clusters []string
-		if err = json.Unmarshal([]byte(val), &clusters); err == nil {
-			if slice.ContainsString(clusters, clusterName) {
-				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
-			}
-		}
+		seen[key] = true
+		requests = append(requests, reconcile.Request{NamespacedName: key})
 	}
 
 	return requests
 }
+
+// targetingGateways returns the gateways whose placement decided the cluster
+func (m *ClusterEventMapper) targetingGateways(ctx context.Context, cluster string) ([]gatewayapiv1.Gateway, error) {
+	decisions := &clusterv1beta1.PlacementDecisionList{}
+	if err := m.Client.List(ctx, decisions); err != nil {
+		return nil, err
+	}
+	gateways := []gatewayapiv1.Gateway{}
+	for _, decision := range decisions.Items {
+		placementName := decision.GetLabels()[placement.OCMPlacementLabel]
+		if placementName == "" || !decidesCluster(&decision, cluster) {
+			continue
+		}
+		list := &gatewayapiv1.GatewayList{}
+		if err := m.Client.List(ctx, list, client.InNamespace(decision.Namespace), client.MatchingFields{GatewayPlacementIndex: placementName}); err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, list.Items...)
+	}
+	return gateways, nil
+}
+
+func decidesCluster(decision *clusterv1beta1.PlacementDecision, cluster string) bool {
+	for _, d := range decision.Status.Decisions {
+		if d.ClusterName == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// gatewayPlacedClusters is the GatewayClustersIndex extractor. It returns the clusters recorded in the
+// kuadrant.io/gateway-clusters annotation of the gateway
+func gatewayPlacedClusters(o client.Object) []string {
+	val := metadata.GetAnnotation(o, GatewayClustersAnnotation)
+	if val == "" {
+		return nil
+	}
+	var clusters []string
+	if err := json.Unmarshal([]byte(val), &clusters); err != nil {
+		return nil
+	}
+	return clusters
+}
+
+// managedClusterChangedPredicate filters out the ManagedCluster updates that cannot affect the gateways placed on
+// the cluster, such as lease renewals. Only changes to the labels, cluster claims or availability are let through
+func managedClusterChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, ok := e.ObjectOld.(*clusterv1.ManagedCluster)
+			if !ok {
+				return true
+			}
+			newCluster, ok := e.ObjectNew.(*clusterv1.ManagedCluster)
+			if !ok {
+				return true
+			}
+			return !equality.Semantic.DeepEqual(oldCluster.Labels, newCluster.Labels) ||
+				!equality.Semantic.DeepEqual(oldCluster.Status.ClusterClaims, newCluster.Status.ClusterClaims) ||
+				clusterAvailability(oldCluster) != clusterAvailability(newCluster)
+		},
+	}
+}
+
+func clusterAvailability(cluster *clusterv1.ManagedCluster) metav1.ConditionStatus {
+	available := meta.FindStatusCondition(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	if available == nil {
+		return metav1.ConditionUnknown
+	}
+	return available.Status
+}
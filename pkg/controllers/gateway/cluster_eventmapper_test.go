@@ -0,0 +1,172 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/capabilities"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestClusterEventMapper(t *testing.T) {
+	placedGateway := func(name, clusters string) gatewayapiv1.Gateway {
+		return gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testutil.Namespace,
+				Annotations: map[string]string{
+					GatewayClustersAnnotation: clusters,
+				},
+			},
+		}
+	}
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1beta1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithLists(&gatewayapiv1.GatewayList{
+			Items: []gatewayapiv1.Gateway{
+				placedGateway("on-both", `["a","b"]`),
+				placedGateway("on-b", `["b"]`),
+				placedGateway("invalid", `a`),
+				{ObjectMeta: metav1.ObjectMeta{Name: "not-placed", Namespace: testutil.Namespace}},
+				// placed on b, and targeting c that it refused until c publishes its capabilities
+				{ObjectMeta: metav1.ObjectMeta{
+					Name:        "targeting",
+					Namespace:   testutil.Namespace,
+					Labels:      map[string]string{placement.OCMPlacementLabel: testutil.Placement},
+					Annotations: map[string]string{GatewayClustersAnnotation: `["b"]`},
+				}},
+			},
+		}).
+		WithObjects(buildTestPlacementDecision(testutil.Namespace, testutil.Placement, "b", "c")).
+		WithIndex(&gatewayapiv1.Gateway{}, GatewayClustersIndex, gatewayPlacedClusters).
+		WithIndex(&gatewayapiv1.Gateway{}, GatewayPlacementIndex, gatewayPlacement).
+		Build()
+	mapper := NewClusterEventMapper(logr.Discard(), client)
+
+	cases := []struct {
+		name     string
+		cluster  *clusterv1.ManagedCluster
+		expected []string
+	}{
+		{
+			name:     "maps to the gateways placed on the cluster",
+			cluster:  &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+			expected: []string{"on-both"},
+		},
+		{
+			name:     "maps to every gateway placed on the cluster",
+			cluster:  &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+			expected: []string{"on-b", "on-both", "targeting"},
+		},
+		{
+			name: "maps a refused cluster publishing its claims to the gateways targeting it",
+			cluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "c"},
+				Status: clusterv1.ManagedClusterStatus{
+					ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: capabilities.GatewayClassesClaim, Value: "istio"}},
+				},
+			},
+			expected: []string{"targeting"},
+		},
+		{
+			name: "ignores deleting clusters",
+			cluster: &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+				Name:              "a",
+				DeletionTimestamp: testutil.GetTime(),
+				Finalizers:        []string{"test"},
+			}},
+			expected: []string{},
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			requests := mapper.MapToGateway(context.TODO(), testCase.cluster)
+			if len(requests) != len(testCase.expected) {
+				t.Fatalf("expected requests for %v but got %v", testCase.expected, requests)
+			}
+			for i, request := range requests {
+				if request.Name != testCase.expected[i] {
+					t.Errorf("expected requests for %v but got %v", testCase.expected, requests)
+				}
+			}
+		})
+	}
+}
+
+func TestManagedClusterChangedPredicate(t *testing.T) {
+	cluster := func(mutate func(*clusterv1.ManagedCluster)) *clusterv1.ManagedCluster {
+		c := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "a",
+				Labels: map[string]string{"kuadrant.io/lb-attribute-geo-code": "EU"},
+			},
+			Status: clusterv1.ManagedClusterStatus{
+				Conditions: []metav1.Condition{
+					{Type: clusterv1.ManagedClusterConditionAvailable, Status: metav1.ConditionTrue},
+				},
+				ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "id.k8s.io", Value: "a"}},
+			},
+		}
+		if mutate != nil {
+			mutate(c)
+		}
+		return c
+	}
+	cases := []struct {
+		name     string
+		updated  *clusterv1.ManagedCluster
+		expected bool
+	}{
+		{
+			name: "ignores heartbeat updates",
+			updated: cluster(func(c *clusterv1.ManagedCluster) {
+				c.ResourceVersion = "2"
+				c.Status.Conditions[0].LastTransitionTime = metav1.Now()
+			}),
+			expected: false,
+		},
+		{
+			name: "label change",
+			updated: cluster(func(c *clusterv1.ManagedCluster) {
+				c.Labels["kuadrant.io/lb-attribute-geo-code"] = "US"
+			}),
+			expected: true,
+		},
+		{
+			name: "claim change",
+			updated: cluster(func(c *clusterv1.ManagedCluster) {
+				c.Status.ClusterClaims = append(c.Status.ClusterClaims, clusterv1.ManagedClusterClaim{Name: "version", Value: "1"})
+			}),
+			expected: true,
+		},
+		{
+			name: "availability change",
+			updated: cluster(func(c *clusterv1.ManagedCluster) {
+				c.Status.Conditions[0].Status = metav1.ConditionUnknown
+			}),
+			expected: true,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := managedClusterChangedPredicate().Update(event.UpdateEvent{ObjectOld: cluster(nil), ObjectNew: testCase.updated})
+			if got != testCase.expected {
+				t.Errorf("expected %v but got %v", testCase.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+const (
+	// ClusterOverridesAppliedConditionType reports whether the cluster overrides of the params result in a valid
+	// gateway on every cluster they select
+	ClusterOverridesAppliedConditionType = LabelPrefix + "ClusterOverridesApplied"
+
+	ClusterOverridesAppliedReason = "OverridesApplied"
+	ClusterOverridesInvalidReason = "OverridesInvalid"
+)
+
+// ClusterOverridesFor returns the cluster overrides of the params that select the cluster, in the order they are applied
+func (p *Params) ClusterOverridesFor(cluster string, clusterLabels map[string]string) []v1alpha1.ClusterOverride {
+	if p == nil {
+		return nil
+	}
+	return slice.Filter(p.ClusterOverrides, func(override v1alpha1.ClusterOverride) bool {
+		return overrideSelects(override, cluster, clusterLabels)
+	})
+}
+
+// ClusterOverrideNames returns the names of the cluster overrides, or nil when there are none
+func ClusterOverrideNames(overrides []v1alpha1.ClusterOverride) []string {
+	if len(overrides) == 0 {
+		return nil
+	}
+	return slice.Map(overrides, func(override v1alpha1.ClusterOverride) string {
+		return override.Name
+	})
+}
+
+func overrideSelects(o v1alpha1.ClusterOverride, cluster string, clusterLabels map[string]string) bool {
+	if slice.ContainsString(o.Clusters, cluster) {
+		return true
+	}
+	if o.ClusterSelector == nil {
+		return false
+	}
+	// the selector is validated when the params are resolved
+	selector, err := metav1.LabelSelectorAsSelector(o.ClusterSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(clusterLabels))
+}
+
+// applyClusterOverride patches the downstream gateway. The patch must result in a valid gateway of the same name and
+// namespace
+func applyClusterOverride(o v1alpha1.ClusterOverride, downstream *gatewayapiv1.Gateway) error {
+	original, err := json.Marshal(downstream)
+	if err != nil {
+		return err
+	}
+	var patched []byte
+	switch o.PatchType {
+	case "", MergePatchType:
+		patched, err = jsonpatch.MergePatch(original, o.Patch.Raw)
+	case JSONPatchType:
+		var patch jsonpatch.Patch
+		if patch, err = jsonpatch.DecodePatch(o.Patch.Raw); err == nil {
+			patched, err = patch.Apply(original)
+		}
+	default:
+		err = fmt.Errorf("unsupported patch type %s", o.PatchType)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := &gatewayapiv1.Gateway{}
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(result); err != nil {
+		return fmt.Errorf("patched gateway is invalid: %w", err)
+	}
+	if result.Name != downstream.Name || result.Namespace != downstream.Namespace {
+		return fmt.Errorf("the name and namespace of the gateway can't be patched")
+	}
+	*downstream = *result
+	return nil
+}
+
+func validateClusterOverrides(overrides []v1alpha1.ClusterOverride) error {
+	names := []string{}
+	for _, override := range overrides {
+		if override.Name == "" {
+			return fmt.Errorf("name must be set")
+		}
+		if slice.ContainsString(names, override.Name) {
+			return fmt.Errorf("duplicate override %s", override.Name)
+		}
+		names = append(names, override.Name)
+		if len(override.Clusters) == 0 && override.ClusterSelector == nil {
+			return fmt.Errorf("override %s must select clusters by name or clusterSelector", override.Name)
+		}
+		if override.ClusterSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(override.ClusterSelector); err != nil {
+				return fmt.Errorf("override %s has an invalid clusterSelector: %w", override.Name, err)
+			}
+		}
+		if len(override.Patch.Raw) == 0 {
+			return fmt.Errorf("override %s must set a patch", override.Name)
+		}
+		switch override.PatchType {
+		case "", MergePatchType:
+			if err := json.Unmarshal(override.Patch.Raw, &map[string]interface{}{}); err != nil {
+				return fmt.Errorf("override %s has an invalid merge patch: %w", override.Name, err)
+			}
+		case JSONPatchType:
+			if _, err := jsonpatch.DecodePatch(override.Patch.Raw); err != nil {
+				return fmt.Errorf("override %s has an invalid json patch: %w", override.Name, err)
+			}
+		default:
+			return fmt.Errorf("override %s has unsupported patchType %s. Must be one of [%s,%s]", override.Name, override.PatchType, MergePatchType, JSONPatchType)
+		}
+	}
+	return nil
+}
+
+// clusterCustomiser returns the customiser refusing the clusters lacking the capabilities required by the params,
+// and setting the downstream class, the assigned addresses and applying the cluster overrides of the params to the
+// gateway placed on each cluster, or nil without params. A cluster whose overrides don't result in a valid gateway
+// is refused, the reason is recorded in overridesRefused when it is not nil
+func (r *GatewayReconciler) clusterCustomiser(params *Params, addresses *addressAssignment, overridesRefused map[string]string) placement.ClusterCustomiser {
+	if params == nil {
+		return nil
+	}
+	return func(ctx context.Context, cluster string, downstream *gatewayapiv1.Gateway) error {
+		managedCluster := &clusterv1.ManagedCluster{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: cluster}, managedCluster); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			managedCluster = nil
+		}
+		if missing, _ := params.MissingCapabilities(managedCluster); len(missing) > 0 {
+			return fmt.Errorf("%w: missing %s", placement.ErrClusterRefused, strings.Join(missing, ", "))
+		}
+		downstream.Spec.GatewayClassName = gatewayapiv1.ObjectName(params.GetDownstreamClassFor(managedCluster))
+		if addresses != nil {
+			downstream.Spec.Addresses = addresses.addressesFor(cluster)
+		}
+		var clusterLabels map[string]string
+		if managedCluster != nil {
+			clusterLabels = managedCluster.Labels
+		}
+		for _, override := range params.ClusterOverridesFor(cluster, clusterLabels) {
+			if err := applyClusterOverride(override, downstream); err != nil {
+				reason := fmt.Sprintf("override %s: %s", override.Name, err)
+				if overridesRefused != nil {
+					overridesRefused[cluster] = reason
+				}
+				return fmt.Errorf("%w: %s", placement.ErrClusterRefused, reason)
+			}
+		}
+		return nil
+	}
+}
+
+// customiseTargets applies the customiser to a copy of the downstream gateway for each targeted cluster without
+// placing the gateway, so that the clusters refused for their overrides are recorded when the gateway is not placed
+func (r *GatewayReconciler) customiseTargets(ctx context.Context, upstream, downstream *gatewayapiv1.Gateway, customise placement.ClusterCustomiser) error {
+	if customise == nil {
+		return nil
+	}
+	targets, err := r.Placement.GetClusters(ctx, upstream)
+	if err != nil {
+		crlog.FromContext(ctx).V(3).Info("failed to get target clusters to apply the cluster overrides to", "error", err)
+		return nil
+	}
+	for _, cluster := range sets.List(targets) {
+		if err := customise(ctx, cluster, downstream.DeepCopy()); err != nil && !errors.Is(err, placement.ErrClusterRefused) {
+			return err
+		}
+	}
+	return nil
+}
+
+// overrideRefusals remembers the clusters each gateway was last refused from because of its cluster overrides, so
+// that the refusals made while reconciling the gateway are reported in its status
+type overrideRefusals struct {
+	mu      sync.Mutex
+	refused map[types.NamespacedName]map[string]string
+}
+
+// set records the clusters the gateway was refused from and the reason of each
+func (o *overrideRefusals) set(gateway types.NamespacedName, refused map[string]string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(refused) == 0 {
+		delete(o.refused, gateway)
+		return
+	}
+	if o.refused == nil {
+		o.refused = map[types.NamespacedName]map[string]string{}
+	}
+	o.refused[gateway] = refused
+}
+
+// get returns the clusters the gateway was last refused from
+func (o *overrideRefusals) get(gateway types.NamespacedName) map[string]string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.refused[gateway]
+}
+
+// forget drops the refusals of a deleted gateway
+func (o *overrideRefusals) forget(gateway types.NamespacedName) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.refused, gateway)
+}
+
+// buildClusterOverridesAppliedCondition reports the clusters the gateway is not placed on because their overrides
+// don't result in a valid gateway. nil is returned when the params have no cluster overrides
+func buildClusterOverridesAppliedCondition(generation int64, params *Params, refused map[string]string) *metav1.Condition {
+	if params == nil || len(params.ClusterOverrides) == 0 {
+		return nil
+	}
+	condition := &metav1.Condition{
+		Type:               ClusterOverridesAppliedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             ClusterOverridesAppliedReason,
+		Message:            "cluster overrides applied",
+		ObservedGeneration: generation,
+	}
+	if len(refused) == 0 {
+		return condition
+	}
+	clusters := []string{}
+	for cluster, reason := range refused {
+		clusters = append(clusters, fmt.Sprintf("%s (%s)", cluster, reason))
+	}
+	sort.Strings(clusters)
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = ClusterOverridesInvalidReason
+	condition.Message = fmt.Sprintf("gateway not placed on clusters with invalid overrides: %s", strings.Join(clusters, "; "))
+	return condition
+}
@@ -0,0 +1,415 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestClusterOverrideApply(t *testing.T) {
+	downstream := func() *gatewayapiv1.Gateway {
+		return &gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: testutil.DummyCRName, Namespace: "kuadrant-" + testutil.Namespace},
+			Spec: gatewayapiv1.GatewaySpec{
+				GatewayClassName: "istio",
+				Listeners: []gatewayapiv1.Listener{
+					{Name: "api", Hostname: testutil.Pointer(gatewayapiv1.Hostname("api.example.com")), Port: 443, Protocol: gatewayapiv1.HTTPSProtocolType},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name        string
+		override    v1alpha1.ClusterOverride
+		expectError bool
+		verify      func(*testing.T, *gatewayapiv1.Gateway)
+	}{
+		{
+			name: "merge patch sets addresses and annotations",
+			override: v1alpha1.ClusterOverride{
+				Name:  "internal-lb",
+				Patch: apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"annotations":{"networking.gke.io/load-balancer-type":"Internal"}},"spec":{"addresses":[{"type":"IPAddress","value":"10.0.0.1"}]}}`)},
+			},
+			verify: func(t *testing.T, gateway *gatewayapiv1.Gateway) {
+				if gateway.Annotations["networking.gke.io/load-balancer-type"] != "Internal" {
+					t.Errorf("expected the load balancer annotation but got %v", gateway.Annotations)
+				}
+				if len(gateway.Spec.Addresses) != 1 || gateway.Spec.Addresses[0].Value != "10.0.0.1" {
+					t.Errorf("expected address 10.0.0.1 but got %v", gateway.Spec.Addresses)
+				}
+				if len(gateway.Spec.Listeners) != 1 {
+					t.Errorf("expected the listeners to be left unchanged but got %v", gateway.Spec.Listeners)
+				}
+			},
+		},
+		{
+			name: "json patch changes a hostname and adds a listener",
+			override: v1alpha1.ClusterOverride{
+				Name:      "eu",
+				PatchType: JSONPatchType,
+				Patch: apiextensionsv1.JSON{Raw: []byte(`[
+					{"op":"replace","path":"/spec/listeners/0/hostname","value":"api.eu.example.com"},
+					{"op":"add","path":"/spec/listeners/-","value":{"name":"internal","hostname":"internal.example.com","port":80,"protocol":"HTTP"}}
+				]`)},
+			},
+			verify: func(t *testing.T, gateway *gatewayapiv1.Gateway) {
+				if len(gateway.Spec.Listeners) != 2 {
+					t.Fatalf("expected 2 listeners but got %v", gateway.Spec.Listeners)
+				}
+				if *gateway.Spec.Listeners[0].Hostname != "api.eu.example.com" || gateway.Spec.Listeners[1].Name != "internal" {
+					t.Errorf("unexpected listeners %v", gateway.Spec.Listeners)
+				}
+			},
+		},
+		{
+			name: "json patch of a missing path",
+			override: v1alpha1.ClusterOverride{
+				Name:      "missing",
+				PatchType: JSONPatchType,
+				Patch:     apiextensionsv1.JSON{Raw: []byte(`[{"op":"replace","path":"/spec/listeners/3/hostname","value":"api.eu.example.com"}]`)},
+			},
+			expectError: true,
+		},
+		{
+			name: "unknown field",
+			override: v1alpha1.ClusterOverride{
+				Name:  "unknown",
+				Patch: apiextensionsv1.JSON{Raw: []byte(`{"spec":{"adresses":[]}}`)},
+			},
+			expectError: true,
+		},
+		{
+			name: "namespace can't be patched",
+			override: v1alpha1.ClusterOverride{
+				Name:  "namespace",
+				Patch: apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"namespace":"other"}}`)},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			gateway := downstream()
+			err := applyClusterOverride(testCase.override, gateway)
+			if (err != nil) != testCase.expectError {
+				t.Fatalf("expected error %v but got %v", testCase.expectError, err)
+			}
+			if testCase.expectError {
+				if !reflect.DeepEqual(gateway, downstream()) {
+					t.Errorf("expected the gateway to be left unchanged on error but got %v", gateway)
+				}
+				return
+			}
+			testCase.verify(t, gateway)
+		})
+	}
+}
+
+func TestValidateClusterOverrides(t *testing.T) {
+	patch := apiextensionsv1.JSON{Raw: []byte(`{"spec":{"addresses":[]}}`)}
+	cases := []struct {
+		name        string
+		overrides   []v1alpha1.ClusterOverride
+		expectError bool
+	}{
+		{
+			name: "valid",
+			overrides: []v1alpha1.ClusterOverride{
+				{Name: "by-name", Clusters: []string{"c1"}, Patch: patch},
+				{Name: "by-selector", ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "aws"}}, Patch: patch},
+				{Name: "json", Clusters: []string{"c1"}, PatchType: JSONPatchType, Patch: apiextensionsv1.JSON{Raw: []byte(`[{"op":"remove","path":"/spec/addresses"}]`)}},
+			},
+		},
+		{
+			name:        "missing name",
+			overrides:   []v1alpha1.ClusterOverride{{Clusters: []string{"c1"}, Patch: patch}},
+			expectError: true,
+		},
+		{
+			name: "duplicate name",
+			overrides: []v1alpha1.ClusterOverride{
+				{Name: "a", Clusters: []string{"c1"}, Patch: patch},
+				{Name: "a", Clusters: []string{"c2"}, Patch: patch},
+			},
+			expectError: true,
+		},
+		{
+			name:        "no clusters selected",
+			overrides:   []v1alpha1.ClusterOverride{{Name: "a", Patch: patch}},
+			expectError: true,
+		},
+		{
+			name: "invalid selector",
+			overrides: []v1alpha1.ClusterOverride{{Name: "a", Patch: patch, ClusterSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "cloud", Operator: "Near"}},
+			}}},
+			expectError: true,
+		},
+		{
+			name:        "missing patch",
+			overrides:   []v1alpha1.ClusterOverride{{Name: "a", Clusters: []string{"c1"}}},
+			expectError: true,
+		},
+		{
+			name:        "merge patch is not an object",
+			overrides:   []v1alpha1.ClusterOverride{{Name: "a", Clusters: []string{"c1"}, Patch: apiextensionsv1.JSON{Raw: []byte(`[]`)}}},
+			expectError: true,
+		},
+		{
+			name:        "invalid json patch",
+			overrides:   []v1alpha1.ClusterOverride{{Name: "a", Clusters: []string{"c1"}, PatchType: JSONPatchType, Patch: patch}},
+			expectError: true,
+		},
+		{
+			name:        "unsupported patch type",
+			overrides:   []v1alpha1.ClusterOverride{{Name: "a", Clusters: []string{"c1"}, PatchType: "strategic", Patch: patch}},
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateClusterOverrides(testCase.overrides)
+			if (err != nil) != testCase.expectError {
+				t.Errorf("expected error %v but got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
+
+func TestClusterCustomiser(t *testing.T) {
+	params := &Params{
+		ClusterOverrides: []v1alpha1.ClusterOverride{
+			{Name: "aws", ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "aws"}}, Patch: apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"annotations":{"lb":"nlb"}}}`)}},
+			{Name: "c2", Clusters: []string{"c2"}, Patch: apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"annotations":{"lb":"internal"}}}`)}},
+		},
+	}
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c1", Labels: map[string]string{"cloud": "aws"}}},
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c2", Labels: map[string]string{"cloud": "aws"}}},
+			).
+			Build(),
+	}
+
+	if customise := r.clusterCustomiser(nil, nil, nil); customise != nil {
+		t.Errorf("expected no customiser without params")
+	}
+
+	customise := r.clusterCustomiser(params, nil, nil)
+	expected := map[string]string{"c1": "nlb", "c2": "internal", "c3": ""}
+	for cluster, lb := range expected {
+		gateway := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+		if err := customise(context.TODO(), cluster, gateway); err != nil {
+			t.Fatalf("did not expect an error for cluster %s but got %v", cluster, err)
+		}
+		if gateway.Annotations["lb"] != lb {
+			t.Errorf("expected lb annotation %q on cluster %s but got %q", lb, cluster, gateway.Annotations["lb"])
+		}
+	}
+}
+
+func TestClusterCustomiserRefusesInvalidOverrides(t *testing.T) {
+	params := &Params{
+		ClusterOverrides: []v1alpha1.ClusterOverride{
+			{Name: "rename", Clusters: []string{"c2"}, Patch: apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"name":"other"}}`)}},
+		},
+	}
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c1"}},
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c2"}},
+			).
+			Build(),
+	}
+
+	refused := map[string]string{}
+	customise := r.clusterCustomiser(params, nil, refused)
+	for _, cluster := range []string{"c1", "c2"} {
+		gateway := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+		err := customise(context.TODO(), cluster, gateway)
+		if cluster == "c1" && err != nil {
+			t.Errorf("did not expect an error for cluster %s but got %v", cluster, err)
+		}
+		if cluster == "c2" && !errors.Is(err, placement.ErrClusterRefused) {
+			t.Errorf("expected cluster %s to be refused but got %v", cluster, err)
+		}
+	}
+	if _, ok := refused["c2"]; !ok || len(refused) != 1 {
+		t.Errorf("expected only c2 to be recorded as refused but got %v", refused)
+	}
+}
+
+func TestOverrideRefusalsOfUnplacedGateways(t *testing.T) {
+	params := &Params{
+		ClusterOverrides: []v1alpha1.ClusterOverride{
+			{Name: "rename", Clusters: []string{testutil.Cluster}, Patch: apiextensionsv1.JSON{Raw: []byte(`{"metadata":{"name":"other"}}`)}},
+		},
+	}
+	gateway := func(annotations map[string]string, listener gatewayapiv1.Listener) *gatewayapiv1.Gateway {
+		return &gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        testutil.DummyCRName,
+				Namespace:   testutil.Namespace,
+				Labels:      getTestGatewayLabels(),
+				Annotations: annotations,
+			},
+			Spec: gatewayapiv1.GatewaySpec{Listeners: []gatewayapiv1.Listener{listener}},
+		}
+	}
+	httpListener := gatewayapiv1.Listener{Name: "http", Protocol: gatewayapiv1.HTTPProtocolType}
+	missingSecretListener := gatewayapiv1.Listener{
+		Name:     "https",
+		Protocol: gatewayapiv1.HTTPSProtocolType,
+		TLS: &gatewayapiv1.GatewayTLSConfig{
+			CertificateRefs: []gatewayapiv1.SecretObjectReference{{Name: "missing"}},
+		},
+	}
+	cases := []struct {
+		name    string
+		gateway *gatewayapiv1.Gateway
+	}{
+		{
+			name:    "paused gateway",
+			gateway: gateway(map[string]string{PausedAnnotation: "true"}, httpListener),
+		},
+		{
+			name:    "gateway without valid listeners",
+			gateway: gateway(nil, missingSecretListener),
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			scheme := testutil.GetValidTestScheme()
+			_ = clusterv1.AddToScheme(scheme)
+			_ = clusterv1beta1.AddToScheme(scheme)
+			_ = workv1.AddToScheme(scheme)
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(
+					&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: testutil.Cluster}},
+					buildTestPlacementDecision(testutil.Namespace, testutil.Placement, testutil.Cluster),
+				).
+				Build()
+			// a newly started controller has no refusals recorded for the gateway
+			r := &GatewayReconciler{
+				Client:    c,
+				Scheme:    scheme,
+				Placement: placement.NewOCMPlacer(c),
+				Recorder:  record.NewFakeRecorder(10),
+			}
+			if _, _, _, _, _, err := r.reconcileDownstreamFromUpstreamGateway(context.TODO(), testCase.gateway, params); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			refused := r.overrideRefusals.get(client.ObjectKeyFromObject(testCase.gateway))
+			if _, ok := refused[testutil.Cluster]; !ok {
+				t.Errorf("expected %s to be recorded as refused for its overrides but got %v", testutil.Cluster, refused)
+			}
+		})
+	}
+}
+
+func TestBuildClusterOverridesAppliedCondition(t *testing.T) {
+	params := &Params{
+		ClusterOverrides: []v1alpha1.ClusterOverride{
+			{Name: "a", Clusters: []string{"c1"}, Patch: apiextensionsv1.JSON{Raw: []byte(`{}`)}},
+		},
+	}
+	cases := []struct {
+		name          string
+		params        *Params
+		refused       map[string]string
+		expectNil     bool
+		expectStatus  metav1.ConditionStatus
+		expectMessage string
+	}{
+		{
+			name:      "no params",
+			expectNil: true,
+		},
+		{
+			name:      "no cluster overrides",
+			params:    &Params{},
+			refused:   map[string]string{"c1": "override a: invalid"},
+			expectNil: true,
+		},
+		{
+			name:          "overrides applied",
+			params:        params,
+			expectStatus:  metav1.ConditionTrue,
+			expectMessage: "cluster overrides applied",
+		},
+		{
+			name:          "clusters refused",
+			params:        params,
+			refused:       map[string]string{"c2": "override b: invalid", "c1": "override a: invalid"},
+			expectStatus:  metav1.ConditionFalse,
+			expectMessage: "gateway not placed on clusters with invalid overrides: c1 (override a: invalid); c2 (override b: invalid)",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			condition := buildClusterOverridesAppliedCondition(1, testCase.params, testCase.refused)
+			if testCase.expectNil {
+				if condition != nil {
+					t.Errorf("expected no condition but got %v", condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("expected a condition")
+			}
+			if condition.Status != testCase.expectStatus || condition.Message != testCase.expectMessage {
+				t.Errorf("expected status %s and message %q but got %s and %q", testCase.expectStatus, testCase.expectMessage, condition.Status, condition.Message)
+			}
+		})
+	}
+}
+
+func TestOverrideRefusals(t *testing.T) {
+	refusals := overrideRefusals{}
+	gateway := types.NamespacedName{Namespace: "test", Name: "test"}
+	refusals.set(gateway, map[string]string{"c1": "override a: invalid"})
+	if !reflect.DeepEqual(refusals.get(gateway), map[string]string{"c1": "override a: invalid"}) {
+		t.Errorf("expected the refusals to be recorded but got %v", refusals.get(gateway))
+	}
+	refusals.set(gateway, map[string]string{})
+	if refusals.get(gateway) != nil {
+		t.Errorf("expected the refusals to be cleared but got %v", refusals.get(gateway))
+	}
+	refusals.set(gateway, map[string]string{"c1": "override a: invalid"})
+	refusals.forget(gateway)
+	if refusals.get(gateway) != nil {
+		t.Errorf("expected the refusals to be forgotten but got %v", refusals.get(gateway))
+	}
+}
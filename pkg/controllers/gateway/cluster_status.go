@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/multicluster"
+)
+
+const (
+	// ClusterStatusKey is the key of the companion ConfigMap holding the serialized per cluster status
+	ClusterStatusKey = "clusters"
+	// ClusterStatusSuffix is appended to the gateway name to build the name of the companion ConfigMap
+	ClusterStatusSuffix = "-clusters"
+	// LabelLBAttributeWeight is the ManagedCluster label used to set the load balancing weight of a cluster
+	LabelLBAttributeWeight = LabelPrefix + "lb-attribute-weight"
+
+	// ClusterStatusPublishedConditionType reports that the companion ConfigMap can not be published
+	ClusterStatusPublishedConditionType = LabelPrefix + "ClusterStatusPublished"
+	// ConfigMapConflictReason is set when a ConfigMap the controller publishes to is not owned by the gateway
+	ConfigMapConflictReason = "ConfigMapConflict"
+)
+
+// errConfigMapConflict is returned when a ConfigMap with the name the controller publishes to exists and is not owned
+// by the gateway
+var errConfigMapConflict = errors.New("exists and is not owned by the gateway")
+
+// ClusterStatus is the structured view of a gateway instance on a single placed cluster.
+// It is published alongside the upstream gateway so that load balancing tooling does not
+// have to parse the "<cluster>/<address>" values in the gateway status
+type ClusterStatus struct {
+	// Cluster is the name of the ManagedCluster the gateway is placed on
+	Cluster string `json:"cluster"`
+	// Addresses are the addresses reported by the downstream gateway
+	Addresses []gatewayapiv1.GatewayStatusAddress `json:"addresses"`
+	// Weight is taken from the kuadrant.io/lb-attribute-weight cluster label when set
+	Weight *int `json:"weight,omitempty"`
+	// Geo is taken from the kuadrant.io/lb-attribute-geo-code cluster label when set
+	Geo string `json:"geo,omitempty"`
+	// Available reflects the ManagedClusterConditionAvailable condition of the cluster
+	Available bool `json:"available"`
+	// Labels are the kuadrant.io/ prefixed labels of the cluster
+	Labels map[string]string `json:"labels,omitempty"`
+	// Overrides are the names of the cluster overrides of the gateway class params applied to the gateway
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+func ClusterStatusName(gateway *gatewayapiv1.Gateway) string {
+	return gateway.Name + ClusterStatusSuffix
+}
+
+// buildClusterStatus returns the structured status of the gateway for the given cluster
+func buildClusterStatus(cluster string, managedCluster *clusterv1.ManagedCluster, addresses []gatewayapiv1.GatewayStatusAddress, params *Params) ClusterStatus {
+	status := ClusterStatus{
+		Cluster:   cluster,
+		Addresses: addresses,
+	}
+	if status.Addresses == nil {
+		status.Addresses = []gatewayapiv1.GatewayStatusAddress{}
+	}
+	if managedCluster == nil {
+		status.Overrides = ClusterOverrideNames(params.ClusterOverridesFor(cluster, nil))
+		return status
+	}
+	status.Overrides = ClusterOverrideNames(params.ClusterOverridesFor(cluster, managedCluster.Labels))
+
+	status.Available = meta.IsStatusConditionTrue(managedCluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	for key, value := range managedCluster.Labels {
+		if !strings.HasPrefix(key, LabelPrefix) {
+			continue
+		}
+		if status.Labels == nil {
+			status.Labels = map[string]string{}
+		}
+		status.Labels[key] = value
+	}
+	status.Geo = managedCluster.Labels[multicluster.LabelLBAttributeGeoCode]
+	if weight, err := strconv.Atoi(managedCluster.Labels[LabelLBAttributeWeight]); err == nil {
+		status.Weight = &weight
+	}
+	return status
+}
+
+// reconcileClusterStatus publishes the per cluster status of the gateway into a ConfigMap owned by the gateway
+func (r *GatewayReconciler) reconcileClusterStatus(ctx context.Context, gateway *gatewayapiv1.Gateway, clusters []string, addresses map[string][]gatewayapiv1.GatewayStatusAddress, params *Params) error {
+	log := crlog.FromContext(ctx)
+	statuses := []ClusterStatus{}
+	for _, cluster := range clusters {
+		managedCluster := &clusterv1.ManagedCluster{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: cluster}, managedCluster); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			managedCluster = nil
+		}
+		statuses = append(statuses, buildClusterStatus(cluster, managedCluster, addresses[cluster], params))
+	}
+
+	serialized, err := json.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+
+	name := ClusterStatusName(gateway)
+	result, err := r.reconcileOwnedConfigMap(ctx, gateway, name, ClusterStatusKey, string(serialized))
+	if err != nil {
+		return fmt.Errorf("failed to reconcile cluster status %s: %w", name, err)
+	}
+	log.V(3).Info("reconciled gateway cluster status", "configmap", name, "result", result)
+	return nil
+}
+
+// reconcileOwnedConfigMap publishes the value under the key of the named ConfigMap owned by the gateway. A ConfigMap
+// of the same name that is not owned by the gateway is left untouched and errConfigMapConflict is returned
+func (r *GatewayReconciler) reconcileOwnedConfigMap(ctx context.Context, gateway *gatewayapiv1.Gateway, name, key, value string) (controllerutil.OperationResult, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: gateway.Namespace,
+		},
+	}
+	return controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.ResourceVersion != "" && !metav1.IsControlledBy(configMap, gateway) {
+			return fmt.Errorf("configmap %s %w", name, errConfigMapConflict)
+		}
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[ManagedLabel] = "true"
+		configMap.Data = map[string]string{
+			key: value,
+		}
+		return controllerutil.SetControllerReference(gateway, configMap, r.Scheme)
+	})
+}
+
+// buildClusterStatusPublishedCondition reports a companion ConfigMap that can not be published because a ConfigMap of
+// the same name is not owned by the gateway, nil is returned otherwise
+func buildClusterStatusPublishedCondition(gateway *gatewayapiv1.Gateway, err error) *metav1.Condition {
+	if !errors.Is(err, errConfigMapConflict) {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:               ClusterStatusPublishedConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             ConfigMapConflictReason,
+		Message:            fmt.Sprintf("the per cluster status is not published, ConfigMap %s exists and is not owned by the gateway", ClusterStatusName(gateway)),
+		ObservedGeneration: gateway.Generation,
+	}
+}
@@ -0,0 +1,201 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/multicluster"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestBuildClusterStatus(t *testing.T) {
+	addresses := []gatewayapiv1.GatewayStatusAddress{
+		{
+			Type:  testutil.Pointer(gatewayapiv1.IPAddressType),
+			Value: "172.31.200.0",
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		managedCluster *clusterv1.ManagedCluster
+		addresses      []gatewayapiv1.GatewayStatusAddress
+		params         *Params
+		want           ClusterStatus
+	}{
+		{
+			name: "cluster with geo, weight and availability",
+			managedCluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: testutil.Cluster,
+					Labels: map[string]string{
+						multicluster.LabelLBAttributeGeoCode: "IE",
+						LabelLBAttributeWeight:               "120",
+						"unrelated":                          "label",
+					},
+				},
+				Status: clusterv1.ManagedClusterStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:   clusterv1.ManagedClusterConditionAvailable,
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+			},
+			addresses: addresses,
+			want: ClusterStatus{
+				Cluster:   testutil.Cluster,
+				Addresses: addresses,
+				Weight:    testutil.Pointer(120),
+				Geo:       "IE",
+				Available: true,
+				Labels: map[string]string{
+					multicluster.LabelLBAttributeGeoCode: "IE",
+					LabelLBAttributeWeight:               "120",
+				},
+			},
+		},
+		{
+			name: "invalid weight is ignored",
+			managedCluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: testutil.Cluster,
+					Labels: map[string]string{
+						LabelLBAttributeWeight: "heavy",
+					},
+				},
+			},
+			want: ClusterStatus{
+				Cluster:   testutil.Cluster,
+				Addresses: []gatewayapiv1.GatewayStatusAddress{},
+				Labels: map[string]string{
+					LabelLBAttributeWeight: "heavy",
+				},
+			},
+		},
+		{
+			name: "cluster overrides",
+			managedCluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   testutil.Cluster,
+					Labels: map[string]string{"cloud": "aws"},
+				},
+			},
+			params: &Params{
+				ClusterOverrides: []v1alpha1.ClusterOverride{
+					{Name: "by-name", Clusters: []string{testutil.Cluster}},
+					{Name: "by-selector", ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "aws"}}},
+					{Name: "other", ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "gcp"}}},
+				},
+			},
+			want: ClusterStatus{
+				Cluster:   testutil.Cluster,
+				Addresses: []gatewayapiv1.GatewayStatusAddress{},
+				Overrides: []string{"by-name", "by-selector"},
+			},
+		},
+		{
+			name:      "missing managed cluster",
+			addresses: addresses,
+			want: ClusterStatus{
+				Cluster:   testutil.Cluster,
+				Addresses: addresses,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := buildClusterStatus(testutil.Cluster, testCase.managedCluster, testCase.addresses, testCase.params)
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("buildClusterStatus() = \ngot:\n%v, \nwant: \n%v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestReconcileClusterStatus(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  testutil.Namespace,
+			UID:        "test",
+			Generation: 2,
+		},
+	}
+	userConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterStatusName(gateway),
+			Namespace: testutil.Namespace,
+		},
+		Data: map[string]string{"user": "data"},
+	}
+
+	testCases := []struct {
+		name          string
+		objects       []client.Object
+		wantConflict  bool
+		wantData      map[string]string
+		wantCondition bool
+	}{
+		{
+			name:     "publishes the cluster status",
+			objects:  []client.Object{gateway},
+			wantData: map[string]string{ClusterStatusKey: "[]"},
+		},
+		{
+			name:          "leaves a configmap not owned by the gateway untouched",
+			objects:       []client.Object{gateway, userConfigMap},
+			wantConflict:  true,
+			wantData:      map[string]string{"user": "data"},
+			wantCondition: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			scheme := testutil.GetValidTestScheme()
+			r := &GatewayReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(testCase.objects...).Build(),
+				Scheme: scheme,
+			}
+			err := r.reconcileClusterStatus(context.TODO(), gateway, nil, nil, nil)
+			if errors.Is(err, errConfigMapConflict) != testCase.wantConflict {
+				t.Fatalf("expected conflict %v but got error %v", testCase.wantConflict, err)
+			}
+			if !testCase.wantConflict && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			configMap := &corev1.ConfigMap{}
+			if err := r.Client.Get(context.TODO(), client.ObjectKeyFromObject(userConfigMap), configMap); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(configMap.Data, testCase.wantData) {
+				t.Errorf("expected data %v but got %v", testCase.wantData, configMap.Data)
+			}
+			if metav1.IsControlledBy(configMap, gateway) == testCase.wantConflict {
+				t.Errorf("expected the configmap to be owned by the gateway only when it is published")
+			}
+			condition := buildClusterStatusPublishedCondition(gateway, err)
+			if (condition != nil) != testCase.wantCondition {
+				t.Errorf("expected condition %v but got %v", testCase.wantCondition, condition)
+			}
+			if condition != nil && (condition.Reason != ConfigMapConflictReason || condition.Status != metav1.ConditionFalse) {
+				t.Errorf("unexpected condition %v", condition)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/capabilities"
+)
+
+const (
+	// DownstreamClassesAcceptedConditionType reports whether the downstream GatewayClass exists and is accepted in
+	// each of the clusters the gateway is placed on
+	DownstreamClassesAcceptedConditionType = LabelPrefix + "DownstreamClassesAccepted"
+
+	DownstreamClassesAcceptedReason  = "DownstreamClassesAccepted"
+	DownstreamClassNotFoundReason    = "DownstreamClassNotFound"
+	DownstreamClassNotAcceptedReason = "DownstreamClassNotAccepted"
+	DownstreamClassesPendingReason   = "DownstreamClassesPending"
+)
+
+// GetDownstreamClassFor returns the downstream GatewayClassName for the cluster. The cluster may be nil when the
+// ManagedCluster is not found, in which case the default downstream class is returned
+func (p *Params) GetDownstreamClassFor(cluster *clusterv1.ManagedCluster) string {
+	if p == nil {
+		return ""
+	}
+	if cluster != nil {
+		for _, downstreamClass := range p.DownstreamClasses {
+			if downstreamClassSelects(downstreamClass, cluster) {
+				return downstreamClass.DownstreamClass
+			}
+		}
+	}
+	return p.GetDownstreamClass()
+}
+
+func downstreamClassSelects(c v1alpha1.ClusterDownstreamClass, cluster *clusterv1.ManagedCluster) bool {
+	if c.ClusterSelector != nil {
+		// the selector is validated when the params are resolved
+		selector, err := metav1.LabelSelectorAsSelector(c.ClusterSelector)
+		if err != nil || !selector.Matches(labels.Set(cluster.Labels)) {
+			return false
+		}
+	}
+	for name, value := range c.ClusterClaims {
+		if !hasClusterClaim(cluster, name, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasClusterClaim(cluster *clusterv1.ManagedCluster, name, value string) bool {
+	for _, claim := range cluster.Status.ClusterClaims {
+		if claim.Name == name && claim.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func validateDownstreamClasses(downstreamClasses []v1alpha1.ClusterDownstreamClass) error {
+	for _, downstreamClass := range downstreamClasses {
+		if errs := validation.IsDNS1123Subdomain(downstreamClass.DownstreamClass); len(errs) > 0 {
+			return fmt.Errorf("invalid downstreamClass %s: %s", downstreamClass.DownstreamClass, strings.Join(errs, ", "))
+		}
+		if downstreamClass.ClusterSelector == nil && len(downstreamClass.ClusterClaims) == 0 {
+			return fmt.Errorf("downstreamClass %s must select clusters by clusterSelector or clusterClaims", downstreamClass.DownstreamClass)
+		}
+		if downstreamClass.ClusterSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(downstreamClass.ClusterSelector); err != nil {
+				return fmt.Errorf("downstreamClass %s has an invalid clusterSelector: %w", downstreamClass.DownstreamClass, err)
+			}
+		}
+	}
+	return nil
+}
+
+// placedDownstreamClass is the state of the downstream GatewayClass in a cluster the gateway is placed on, as
+// published in the capability claims of the cluster
+type placedDownstreamClass struct {
+	cluster string
+	name    string
+	// reported is false until the cluster reports its capabilities
+	reported bool
+	exists   bool
+	accepted bool
+}
+
+// placedDownstreamClasses returns the state of the downstream GatewayClass selected by the params for each of the
+// clusters. The classes are read from the capability claims rather than placed on the clusters, so that the gateway
+// is applied whether or not its class exists
+func (r *GatewayReconciler) placedDownstreamClasses(ctx context.Context, params *Params, clusters []string) ([]placedDownstreamClass, error) {
+	downstreamClasses := []placedDownstreamClass{}
+	for _, cluster := range clusters {
+		managedCluster := &clusterv1.ManagedCluster{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: cluster}, managedCluster); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			managedCluster = nil
+		}
+		downstreamClass := placedDownstreamClass{cluster: cluster, name: params.GetDownstreamClassFor(managedCluster)}
+		if downstreamClass.name == "" {
+			continue
+		}
+		if managedCluster != nil {
+			if discovered := capabilities.FromClusterClaims(managedCluster.Status.ClusterClaims); discovered != nil {
+				downstreamClass.reported = true
+				downstreamClass.exists = slice.ContainsString(discovered.GatewayClasses, downstreamClass.name)
+				downstreamClass.accepted = slice.ContainsString(discovered.AcceptedGatewayClasses, downstreamClass.name)
+			}
+		}
+		downstreamClasses = append(downstreamClasses, downstreamClass)
+	}
+	sort.Slice(downstreamClasses, func(i, j int) bool {
+		return downstreamClasses[i].cluster < downstreamClasses[j].cluster
+	})
+	return downstreamClasses, nil
+}
+
+// buildDownstreamClassesAcceptedCondition reports the clusters where the downstream GatewayClass is missing or not
+// accepted. nil is returned when the gateway is not placed on any cluster
+func buildDownstreamClassesAcceptedCondition(generation int64, downstreamClasses []placedDownstreamClass) *metav1.Condition {
+	if len(downstreamClasses) == 0 {
+		return nil
+	}
+	notFound := []string{}
+	notAccepted := []string{}
+	pending := []string{}
+	for _, downstreamClass := range downstreamClasses {
+		switch {
+		case !downstreamClass.reported:
+			pending = append(pending, downstreamClass.cluster)
+		case !downstreamClass.exists:
+			notFound = append(notFound, fmt.Sprintf("%s %s", downstreamClass.cluster, downstreamClass.name))
+		case !downstreamClass.accepted:
+			notAccepted = append(notAccepted, fmt.Sprintf("%s %s", downstreamClass.cluster, downstreamClass.name))
+		}
+	}
+	condition := &metav1.Condition{
+		Type:               DownstreamClassesAcceptedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             DownstreamClassesAcceptedReason,
+		Message:            "downstream gateway classes are accepted in all clusters",
+		ObservedGeneration: generation,
+	}
+	if len(notFound) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = DownstreamClassNotFoundReason
+		condition.Message = fmt.Sprintf("downstream gateway classes not found: %s", strings.Join(notFound, "; "))
+	} else if len(notAccepted) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = DownstreamClassNotAcceptedReason
+		condition.Message = fmt.Sprintf("downstream gateway classes not accepted: %s", strings.Join(notAccepted, "; "))
+	} else if len(pending) > 0 {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = DownstreamClassesPendingReason
+		condition.Message = fmt.Sprintf("clusters not reporting their gateway classes: %s", strings.Join(pending, "; "))
+	}
+	return condition
+}
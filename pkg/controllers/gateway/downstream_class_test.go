@@ -0,0 +1,305 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/capabilities"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestGetDownstreamClassFor(t *testing.T) {
+	params := &Params{
+		DownstreamClass: "istio",
+		DownstreamClasses: []v1alpha1.ClusterDownstreamClass{
+			{DownstreamClass: "gke-l7-rilb", ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "gcp"}}},
+			{DownstreamClass: "openshift-default", ClusterClaims: map[string]string{"product.open-cluster-management.io": "OpenShift"}},
+			{
+				DownstreamClass: "eg",
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "aws"}},
+				ClusterClaims:   map[string]string{"gateway.kuadrant.io/envoy": "true"},
+			},
+		},
+	}
+	cluster := func(labels map[string]string, claims ...clusterv1.ManagedClusterClaim) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "c1", Labels: labels},
+			Status:     clusterv1.ManagedClusterStatus{ClusterClaims: claims},
+		}
+	}
+
+	cases := []struct {
+		name     string
+		params   *Params
+		cluster  *clusterv1.ManagedCluster
+		expected string
+	}{
+		{
+			name:     "nil params",
+			cluster:  cluster(nil),
+			expected: "",
+		},
+		{
+			name:     "selected by labels",
+			params:   params,
+			cluster:  cluster(map[string]string{"cloud": "gcp"}),
+			expected: "gke-l7-rilb",
+		},
+		{
+			name:     "selected by claims",
+			params:   params,
+			cluster:  cluster(nil, clusterv1.ManagedClusterClaim{Name: "product.open-cluster-management.io", Value: "OpenShift"}),
+			expected: "openshift-default",
+		},
+		{
+			name:     "selected by labels and claims",
+			params:   params,
+			cluster:  cluster(map[string]string{"cloud": "aws"}, clusterv1.ManagedClusterClaim{Name: "gateway.kuadrant.io/envoy", Value: "true"}),
+			expected: "eg",
+		},
+		{
+			name:     "labels match but claims don't",
+			params:   params,
+			cluster:  cluster(map[string]string{"cloud": "aws"}, clusterv1.ManagedClusterClaim{Name: "gateway.kuadrant.io/envoy", Value: "false"}),
+			expected: "istio",
+		},
+		{
+			name:     "first match wins",
+			params:   params,
+			cluster:  cluster(map[string]string{"cloud": "gcp"}, clusterv1.ManagedClusterClaim{Name: "product.open-cluster-management.io", Value: "OpenShift"}),
+			expected: "gke-l7-rilb",
+		},
+		{
+			name:     "cluster not found",
+			params:   params,
+			expected: "istio",
+		},
+		{
+			name:     "no downstream classes",
+			params:   &Params{DownstreamClass: "istio"},
+			cluster:  cluster(map[string]string{"cloud": "gcp"}),
+			expected: "istio",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if downstreamClass := testCase.params.GetDownstreamClassFor(testCase.cluster); downstreamClass != testCase.expected {
+				t.Errorf("expected downstream class %q but got %q", testCase.expected, downstreamClass)
+			}
+		})
+	}
+}
+
+func TestValidateDownstreamClasses(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "aws"}}
+	cases := []struct {
+		name              string
+		downstreamClasses []v1alpha1.ClusterDownstreamClass
+		expectError       bool
+	}{
+		{
+			name: "valid",
+			downstreamClasses: []v1alpha1.ClusterDownstreamClass{
+				{DownstreamClass: "eg", ClusterSelector: selector},
+				{DownstreamClass: "openshift-default", ClusterClaims: map[string]string{"product.open-cluster-management.io": "OpenShift"}},
+			},
+		},
+		{
+			name:              "invalid class name",
+			downstreamClasses: []v1alpha1.ClusterDownstreamClass{{DownstreamClass: "Envoy Gateway", ClusterSelector: selector}},
+			expectError:       true,
+		},
+		{
+			name:              "no clusters selected",
+			downstreamClasses: []v1alpha1.ClusterDownstreamClass{{DownstreamClass: "eg"}},
+			expectError:       true,
+		},
+		{
+			name: "invalid selector",
+			downstreamClasses: []v1alpha1.ClusterDownstreamClass{{DownstreamClass: "eg", ClusterSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "cloud", Operator: "Near"}},
+			}}},
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateDownstreamClasses(testCase.downstreamClasses)
+			if (err != nil) != testCase.expectError {
+				t.Errorf("expected error %v but got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
+
+func TestBuildDownstreamClassesAcceptedCondition(t *testing.T) {
+	accepted := func(cluster string) placedDownstreamClass {
+		return placedDownstreamClass{cluster: cluster, name: "istio", reported: true, exists: true, accepted: true}
+	}
+	notAccepted := func(cluster string) placedDownstreamClass {
+		return placedDownstreamClass{cluster: cluster, name: "istio", reported: true, exists: true}
+	}
+
+	cases := []struct {
+		name              string
+		downstreamClasses []placedDownstreamClass
+		expectedStatus    metav1.ConditionStatus
+		expectedReason    string
+	}{
+		{
+			name: "not placed",
+		},
+		{
+			name:              "accepted in all clusters",
+			downstreamClasses: []placedDownstreamClass{accepted("c1"), accepted("c2")},
+			expectedStatus:    metav1.ConditionTrue,
+			expectedReason:    DownstreamClassesAcceptedReason,
+		},
+		{
+			name: "missing in a cluster",
+			downstreamClasses: []placedDownstreamClass{
+				notAccepted("c1"),
+				{cluster: "c2", name: "istio", reported: true},
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: DownstreamClassNotFoundReason,
+		},
+		{
+			name: "not accepted in a cluster",
+			downstreamClasses: []placedDownstreamClass{
+				notAccepted("c1"),
+				{cluster: "c2", name: "istio"},
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: DownstreamClassNotAcceptedReason,
+		},
+		{
+			name: "not yet reported",
+			downstreamClasses: []placedDownstreamClass{
+				accepted("c1"),
+				{cluster: "c2", name: "istio"},
+			},
+			expectedStatus: metav1.ConditionUnknown,
+			expectedReason: DownstreamClassesPendingReason,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			condition := buildDownstreamClassesAcceptedCondition(1, testCase.downstreamClasses)
+			if testCase.expectedStatus == "" {
+				if condition != nil {
+					t.Errorf("expected no condition but got %v", condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("expected a condition but got nil")
+			}
+			if condition.Status != testCase.expectedStatus || condition.Reason != testCase.expectedReason {
+				t.Errorf("expected %s %s but got %s %s: %s", testCase.expectedStatus, testCase.expectedReason, condition.Status, condition.Reason, condition.Message)
+			}
+			if condition.Type != DownstreamClassesAcceptedConditionType || condition.ObservedGeneration != 1 {
+				t.Errorf("unexpected condition %v", condition)
+			}
+		})
+	}
+}
+
+func TestClusterCustomiserDownstreamClass(t *testing.T) {
+	params := &Params{
+		DownstreamClass: "istio",
+		DownstreamClasses: []v1alpha1.ClusterDownstreamClass{
+			{DownstreamClass: "eg", ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "aws"}}},
+		},
+	}
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c1", Labels: map[string]string{"cloud": "aws"}}},
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c2", Labels: map[string]string{"cloud": "gcp"}}},
+			).
+			Build(),
+	}
+
+	customise := r.clusterCustomiser(params, nil, nil)
+	if customise == nil {
+		t.Fatalf("expected a customiser with downstream classes")
+	}
+	expected := map[string]gatewayapiv1.ObjectName{"c1": "eg", "c2": "istio", "c3": "istio"}
+	for cluster, downstreamClass := range expected {
+		gateway := &gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: "istio"},
+		}
+		if err := customise(context.TODO(), cluster, gateway); err != nil {
+			t.Fatalf("did not expect an error for cluster %s but got %v", cluster, err)
+		}
+		if gateway.Spec.GatewayClassName != downstreamClass {
+			t.Errorf("expected downstream class %s on cluster %s but got %s", downstreamClass, cluster, gateway.Spec.GatewayClassName)
+		}
+	}
+}
+
+func TestPlacedDownstreamClasses(t *testing.T) {
+	params := &Params{
+		DownstreamClass: "istio",
+		DownstreamClasses: []v1alpha1.ClusterDownstreamClass{
+			{DownstreamClass: "eg", ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"cloud": "aws"}}},
+		},
+	}
+	reported := func(name string, labels map[string]string, claims ...clusterv1.ManagedClusterClaim) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status: clusterv1.ManagedClusterStatus{
+				ClusterClaims: append(claims, clusterv1.ManagedClusterClaim{Name: capabilities.ReportedClaim, Value: capabilities.Version}),
+			},
+		}
+	}
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				reported("c1", map[string]string{"cloud": "aws"},
+					clusterv1.ManagedClusterClaim{Name: capabilities.GatewayClassesClaim, Value: "eg,istio"},
+					clusterv1.ManagedClusterClaim{Name: capabilities.AcceptedGatewayClassesClaim, Value: "eg"},
+				),
+				reported("c2", nil, clusterv1.ManagedClusterClaim{Name: capabilities.GatewayClassesClaim, Value: "istio"}),
+				reported("c3", nil),
+				&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c4"}},
+			).
+			Build(),
+	}
+
+	downstreamClasses, err := r.placedDownstreamClasses(context.TODO(), params, []string{"c5", "c4", "c3", "c2", "c1"})
+	if err != nil {
+		t.Fatalf("did not expect an error but got %v", err)
+	}
+	expected := []placedDownstreamClass{
+		{cluster: "c1", name: "eg", reported: true, exists: true, accepted: true},
+		{cluster: "c2", name: "istio", reported: true, exists: true},
+		{cluster: "c3", name: "istio", reported: true},
+		{cluster: "c4", name: "istio"},
+		{cluster: "c5", name: "istio"},
+	}
+	if !reflect.DeepEqual(downstreamClasses, expected) {
+		t.Errorf("expected downstream classes %v but got %v", expected, downstreamClasses)
+	}
+}
@@ -0,0 +1,133 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/multicluster"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+// hubOnlyAnnotations are set on the hub objects by the controllers or clients and are never propagated to the spokes
+var hubOnlyAnnotations = []string{
+	GatewayClustersAnnotation,
+	GatewayClusterLabelSelectorAnnotation,
+	ClusterAddressesAnnotation,
+	AssignedAddressesAnnotation,
+	PlanAnnotation,
+	PausedAnnotation,
+	placement.ResyncAnnotation,
+	corev1.LastAppliedConfigAnnotation,
+}
+
+func isHubOnlyAnnotation(key string) bool {
+	return slice.ContainsString(hubOnlyAnnotations, key)
+}
+
+func isHubOnlyLabel(key string) bool {
+	return strings.HasPrefix(key, multicluster.ClustersLabelPrefix) || key == placement.OCMPlacementLabel
+}
+
+// ApplyDownstreamMetadata replaces the labels and annotations copied from the hub with the ones that are
+// propagated to the spokes. Hub only metadata is always removed, the rest is filtered and injected according
+// to the DownstreamMetadata of the params
+func (p *Params) ApplyDownstreamMetadata(obj metav1.Object) {
+	rules := v1alpha1.DownstreamMetadata{}
+	if p != nil && p.DownstreamMetadata != nil {
+		rules = *p.DownstreamMetadata
+	}
+	obj.SetLabels(applyMetadataRules(rules.Labels, obj.GetLabels(), isHubOnlyLabel))
+	obj.SetAnnotations(applyMetadataRules(rules.Annotations, obj.GetAnnotations(), isHubOnlyAnnotation))
+}
+
+// applyMetadataRules returns the keys of in that are not hub only and are allowed and not denied by the rules, plus
+// the injected keys. A nil map is returned when there are no keys left
+func applyMetadataRules(r v1alpha1.MetadataRules, in map[string]string, hubOnly func(string) bool) map[string]string {
+	out := map[string]string{}
+	for key, value := range in {
+		if hubOnly(key) || !metadataRulesAllow(r, key) {
+			continue
+		}
+		out[key] = value
+	}
+	for key, value := range r.Inject {
+		out[key] = value
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func metadataRulesAllow(r v1alpha1.MetadataRules, key string) bool {
+	if len(r.Allow) > 0 && !matchesAny(r.Allow, key) {
+		return false
+	}
+	return !matchesAny(r.Deny, key)
+}
+
+// matchesAny checks the key against the patterns, where * matches any sequence of characters including /
+func matchesAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if matchesPattern(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPattern(pattern, key string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == key
+	}
+	if !strings.HasPrefix(key, parts[0]) {
+		return false
+	}
+	key = key[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(key, part)
+		if i < 0 {
+			return false
+		}
+		key = key[i+len(part):]
+	}
+	last := parts[len(parts)-1]
+	return len(key) >= len(last) && strings.HasSuffix(key, last)
+}
+
+func validateDownstreamMetadata(m *v1alpha1.DownstreamMetadata) error {
+	if err := validateMetadataRules(m.Labels); err != nil {
+		return fmt.Errorf("labels: %w", err)
+	}
+	for key, value := range m.Labels.Inject {
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("labels: invalid value %s for %s: %s", value, key, strings.Join(errs, ", "))
+		}
+	}
+	if err := validateMetadataRules(m.Annotations); err != nil {
+		return fmt.Errorf("annotations: %w", err)
+	}
+	return nil
+}
+
+func validateMetadataRules(r v1alpha1.MetadataRules) error {
+	for _, pattern := range append(append([]string{}, r.Allow...), r.Deny...) {
+		if pattern == "" {
+			return fmt.Errorf("patterns must not be empty")
+		}
+	}
+	for key := range r.Inject {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid key %s: %s", key, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
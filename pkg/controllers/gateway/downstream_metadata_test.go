@@ -0,0 +1,154 @@
+//go:build unit
+
+package gateway
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kuadrant/kuadrant-operator/pkg/multicluster"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+func TestApplyDownstreamMetadata(t *testing.T) {
+	upstream := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"app":                                   "test",
+					"team":                                  "a",
+					multicluster.ClustersLabelPrefix + "c1": "true",
+					placement.OCMPlacementLabel:             "gateway-placement",
+				},
+				Annotations: map[string]string{
+					"example.com/owner":                   "team-a",
+					"kuadrant.io/internal":                "true",
+					GatewayClustersAnnotation:             `["c1"]`,
+					GatewayClusterLabelSelectorAnnotation: "type=test",
+					corev1.LastAppliedConfigAnnotation:    "{}",
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name                string
+		params              *Params
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:                "hub only metadata is removed without params",
+			params:              nil,
+			expectedLabels:      map[string]string{"app": "test", "team": "a"},
+			expectedAnnotations: map[string]string{"example.com/owner": "team-a", "kuadrant.io/internal": "true"},
+		},
+		{
+			name:                "hub only metadata is removed without downstream metadata",
+			params:              &Params{DownstreamClass: "istio"},
+			expectedLabels:      map[string]string{"app": "test", "team": "a"},
+			expectedAnnotations: map[string]string{"example.com/owner": "team-a", "kuadrant.io/internal": "true"},
+		},
+		{
+			name: "allowed keys are propagated",
+			params: &Params{DownstreamMetadata: &v1alpha1.DownstreamMetadata{
+				Labels:      v1alpha1.MetadataRules{Allow: []string{"app"}},
+				Annotations: v1alpha1.MetadataRules{Allow: []string{"example.com/*"}},
+			}},
+			expectedLabels:      map[string]string{"app": "test"},
+			expectedAnnotations: map[string]string{"example.com/owner": "team-a"},
+		},
+		{
+			name: "denied keys are not propagated",
+			params: &Params{DownstreamMetadata: &v1alpha1.DownstreamMetadata{
+				Labels:      v1alpha1.MetadataRules{Allow: []string{"*"}, Deny: []string{"team"}},
+				Annotations: v1alpha1.MetadataRules{Deny: []string{"kuadrant.io/*"}},
+			}},
+			expectedLabels:      map[string]string{"app": "test"},
+			expectedAnnotations: map[string]string{"example.com/owner": "team-a"},
+		},
+		{
+			name: "injected keys take precedence",
+			params: &Params{DownstreamMetadata: &v1alpha1.DownstreamMetadata{
+				Labels:      v1alpha1.MetadataRules{Inject: map[string]string{"istio.io/rev": "canary", "team": "b"}},
+				Annotations: v1alpha1.MetadataRules{Deny: []string{"*"}, Inject: map[string]string{"example.com/mesh": "true"}},
+			}},
+			expectedLabels:      map[string]string{"app": "test", "team": "b", "istio.io/rev": "canary"},
+			expectedAnnotations: map[string]string{"example.com/mesh": "true"},
+		},
+		{
+			name: "patterns match across the prefix",
+			params: &Params{DownstreamMetadata: &v1alpha1.DownstreamMetadata{
+				Labels:      v1alpha1.MetadataRules{Allow: []string{"a*"}},
+				Annotations: v1alpha1.MetadataRules{Allow: []string{"*.com*owner"}},
+			}},
+			expectedLabels:      map[string]string{"app": "test"},
+			expectedAnnotations: map[string]string{"example.com/owner": "team-a"},
+		},
+		{
+			name: "no metadata left",
+			params: &Params{DownstreamMetadata: &v1alpha1.DownstreamMetadata{
+				Labels:      v1alpha1.MetadataRules{Deny: []string{"*"}},
+				Annotations: v1alpha1.MetadataRules{Deny: []string{"*"}},
+			}},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			secret := upstream()
+			testCase.params.ApplyDownstreamMetadata(secret)
+			if !reflect.DeepEqual(secret.Labels, testCase.expectedLabels) {
+				t.Errorf("expected labels %v but got %v", testCase.expectedLabels, secret.Labels)
+			}
+			if !reflect.DeepEqual(secret.Annotations, testCase.expectedAnnotations) {
+				t.Errorf("expected annotations %v but got %v", testCase.expectedAnnotations, secret.Annotations)
+			}
+		})
+	}
+}
+
+func TestValidateDownstreamMetadata(t *testing.T) {
+	cases := []struct {
+		name        string
+		metadata    v1alpha1.DownstreamMetadata
+		expectError bool
+	}{
+		{
+			name: "valid",
+			metadata: v1alpha1.DownstreamMetadata{
+				Labels:      v1alpha1.MetadataRules{Allow: []string{"app", "*.example.com/*"}, Inject: map[string]string{"istio.io/rev": "canary"}},
+				Annotations: v1alpha1.MetadataRules{Deny: []string{"kuadrant.io/*"}, Inject: map[string]string{"example.com/note": "any value"}},
+			},
+		},
+		{
+			name:        "empty pattern",
+			metadata:    v1alpha1.DownstreamMetadata{Annotations: v1alpha1.MetadataRules{Allow: []string{""}}},
+			expectError: true,
+		},
+		{
+			name:        "invalid injected key",
+			metadata:    v1alpha1.DownstreamMetadata{Annotations: v1alpha1.MetadataRules{Inject: map[string]string{"not a key": "value"}}},
+			expectError: true,
+		},
+		{
+			name:        "invalid injected label value",
+			metadata:    v1alpha1.DownstreamMetadata{Labels: v1alpha1.MetadataRules{Inject: map[string]string{"istio.io/rev": "not a value"}}},
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateDownstreamMetadata(&testCase.metadata)
+			if (err != nil) != testCase.expectError {
+				t.Errorf("expected error %v but got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package gateway
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,11 +26,13 @@ import (
 	"strings"
 	"time"
 
+	certmanv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta1"
 	workv1 "open-cluster-management.io/api/work/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,6 +41,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -47,12 +51,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/kuadrant/kuadrant-operator/pkg/multicluster"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/policysync"
 )
 
@@ -62,12 +69,18 @@ const (
 	GatewayClustersAnnotation             = LabelPrefix + "gateway-clusters"
 	GatewayFinalizer                      = LabelPrefix + "gateway"
 	ManagedLabel                          = LabelPrefix + "managed"
+
+	// TLSSecretMissingReason is the reason of the warning event emitted when a listener references a missing secret
+	TLSSecretMissingReason = "TLSSecretMissing"
+	// InvalidParamsReason is the reason of the warning event emitted when the params of the gateway class are invalid
+	InvalidParamsReason = "InvalidParams"
 )
 
 type GatewayPlacer interface {
 	//Place will use the placement logic to create the needed resources and ensure the objects are synced to the targeted clusters
 	// it will return the set of clusters it has targeted
-	Place(ctx context.Context, upstream *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, children ...metav1.Object) (sets.Set[string], error)
+	// customise, when set, is applied to a copy of the downstream gateway for each cluster
+	Place(ctx context.Context, upstream *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, customise placement.ClusterCustomiser, children ...metav1.Object) (sets.Set[string], error)
 	// gets the clusters the gateway has actually been placed on
 	GetPlacedClusters(ctx context.Context, gateway *gatewayapiv1.Gateway) (sets.Set[string], error)
 	//GetClusters returns the clusters decided on by the placement logic
@@ -76,6 +89,17 @@ type GatewayPlacer interface {
 	ListenerTotalAttachedRoutes(ctx context.Context, gateway *gatewayapiv1.Gateway, listenerName string, downstream string) (int, error)
 	// GetAddresses will look at the downstream view of the gateway and return the LB addresses used for these gateways
 	GetAddresses(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) ([]gatewayapiv1.GatewayAddress, error)
+	// GetConditions returns the status conditions of the downstream gateway
+	GetConditions(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) ([]metav1.Condition, error)
+	// ListenerConditions returns the status conditions of a listener from the downstream gateway
+	ListenerConditions(ctx context.Context, gateway *gatewayapiv1.Gateway, listenerName string, downstream string) ([]metav1.Condition, error)
+	// GetSecretAnnotations returns the annotations of the secrets placed with the gateway as applied in the downstream cluster keyed by secret name
+	GetSecretAnnotations(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) (map[string]map[string]string, error)
+	// Plan returns the changes Place would make to each cluster without placing the gateway
+	Plan(ctx context.Context, upstream *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, customise placement.ClusterCustomiser, children ...metav1.Object) (*placement.Plan, error)
+	// Remove removes the gateway and its objects from every cluster without a grace period, returning the clusters
+	// it could not be removed from
+	Remove(ctx context.Context, upstream *gatewayapiv1.Gateway) (sets.Set[string], error)
 }
 
 // +kubebuilder:rbac:groups="",resources=configmaps;events,verbs=get;list;watch;create;update;delete;deletecollection;patch
@@ -96,6 +120,7 @@ type GatewayPlacer interface {
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/finalizers,verbs=update
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=placementdecisions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;delete
 // +kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;create;update;patch;delete
@@ -110,6 +135,22 @@ type GatewayReconciler struct {
 	PolicyInformersManager *policysync.PolicyInformersManager
 	DynamicClient          dynamic.Interface
 	WatchedPolicies        map[schema.GroupVersionResource]cache.ResourceEventHandlerRegistration
+	Recorder               record.EventRecorder
+	// CertificateExpiryWarning is how long before expiry a warning event is emitted for a placed certificate
+	CertificateExpiryWarning time.Duration
+	expiryWarnings           expiryWarnings
+	overrideRefusals         overrideRefusals
+	// EncryptSecrets is set when the placed secrets are encrypted, clusters without an encryption key are refused
+	// the gateways placing secrets
+	EncryptSecrets bool
+}
+
+// eventf records an event on the gateway when the reconciler has an event recorder
+func (r *GatewayReconciler) eventf(gateway *gatewayapiv1.Gateway, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(gateway, eventType, reason, messageFmt, args...)
 }
 
 func isDeleting(g *gatewayapiv1.Gateway) bool {
@@ -124,15 +165,23 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		if err := client.IgnoreNotFound(err); err != nil {
 			return ctrl.Result{}, err
 		}
+		r.expiryWarnings.forget(req.NamespacedName)
+		r.overrideRefusals.forget(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 	upstreamGateway := previous.DeepCopy()
 	log.V(3).Info("reconciling gateway", "classname", upstreamGateway.Spec.GatewayClassName)
 	if isDeleting(upstreamGateway) {
+		// the deletion of a gateway never placed by the controller is left to its own controller
+		if !controllerutil.ContainsFinalizer(upstreamGateway, GatewayFinalizer) {
+			return ctrl.Result{}, nil
+		}
 		log.Info("gateway being deleted ", "gateway", upstreamGateway.Name, "namespace", upstreamGateway.Namespace)
-		if _, _, _, err := r.reconcileDownstreamFromUpstreamGateway(ctx, upstreamGateway, nil); client.IgnoreNotFound(err) != nil {
+		if _, _, _, _, _, err := r.reconcileDownstreamFromUpstreamGateway(ctx, upstreamGateway, nil); client.IgnoreNotFound(err) != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to reconcile downstream gateway after upstream gateway deleted: %s ", err)
 		}
+		deleteCertificateMetrics(upstreamGateway)
+		deletePlacementMetrics(upstreamGateway)
 		controllerutil.RemoveFinalizer(upstreamGateway, GatewayFinalizer)
 		if err := r.Update(ctx, upstreamGateway); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer from gateway : %s", err)
@@ -141,6 +190,18 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, nil
 	}
 
+	// a gateway whose class was deleted or moved to another controller is left as placed. It keeps its finalizer so
+	// that it is removed from the clusters when it is deleted
+	managed, err := isManagedGatewayClass(ctx, r.Client, string(upstreamGateway.Spec.GatewayClassName))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get gateway class : %w", err)
+	}
+	if !managed {
+		log.V(3).Info("gateway class not managed, skipping", "gateway", upstreamGateway.Name, "gatewayclass", upstreamGateway.Spec.GatewayClassName)
+		deletePlacementMetrics(upstreamGateway)
+		return ctrl.Result{}, nil
+	}
+
 	if !controllerutil.ContainsFinalizer(upstreamGateway, GatewayFinalizer) {
 		controllerutil.AddFinalizer(upstreamGateway, GatewayFinalizer)
 		if err = r.Update(ctx, upstreamGateway); err != nil {
@@ -162,6 +223,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		meta.SetStatusCondition(&upstreamGateway.Status.Conditions, programmedCondition)
 
 		if !reflect.DeepEqual(upstreamGateway, previous) {
+			r.eventf(upstreamGateway, corev1.EventTypeWarning, InvalidParamsReason, "invalid parameters in gateway class %s: %s", upstreamGateway.Spec.GatewayClassName, err)
 			err = r.Status().Update(ctx, upstreamGateway)
 			if err != nil {
 				return ctrl.Result{}, err
@@ -174,8 +236,8 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, fmt.Errorf("gateway class err %s ", err)
 	}
 	//if we get to the point where we are going to reconcile the gateway in to the downstream then the upstream gateway is considered accepted
-	// checking not true covers "unknown" and false
-	if !meta.IsStatusConditionTrue(upstreamGateway.Status.Conditions, string(gatewayapiv1.GatewayConditionAccepted)) {
+	// a false accepted condition is aggregated from the downstream gateways so only a missing or unknown condition is initialised here
+	if accepted := meta.FindStatusCondition(upstreamGateway.Status.Conditions, string(gatewayapiv1.GatewayConditionAccepted)); accepted == nil || accepted.Status == metav1.ConditionUnknown {
 		log.V(3).Info("gateway is accepted setting initial programmed and accepted status")
 		acceptedCondition := buildAcceptedCondition(upstreamGateway.Generation, metav1.ConditionTrue)
 		programmedCondition := buildProgrammedCondition(upstreamGateway.Generation, []string{}, metav1.ConditionUnknown, nil)
@@ -185,7 +247,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	log.V(3).Info("gateway pre downstream", "labels", upstreamGateway.Labels)
-	requeue, programmedStatus, clusters, reconcileErr := r.reconcileDownstreamFromUpstreamGateway(ctx, upstreamGateway, params)
+	requeue, programmedStatus, clusters, invalidListeners, addresses, reconcileErr := r.reconcileDownstreamFromUpstreamGateway(ctx, upstreamGateway, params)
 	log.V(3).Info("gateway post downstream", "labels", upstreamGateway.Labels)
 	// gateway now in expected state, place gateway and its associated objects in correct places. Update gateway spec/metadata
 	log.V(3).Info("reconcileDownstreamFromUpstreamGateway result ", "requeue", requeue, "status", programmedStatus, "clusters", clusters, "Err", reconcileErr)
@@ -225,6 +287,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	var addressErr error
 	allAddresses := []gatewayapiv1.GatewayStatusAddress{}
+	clusterAddresses := map[string][]gatewayapiv1.GatewayStatusAddress{}
 	for _, cluster := range clusters {
 		log.V(3).Info("checking cluster for addresses", "cluster", cluster)
 		addresses, addressErr := r.Placement.GetAddresses(ctx, upstreamGateway, cluster)
@@ -242,6 +305,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				Type:  &addressType,
 				Value: fmt.Sprintf("%s/%s", cluster, address.Value),
 			})
+			clusterAddresses[cluster] = append(clusterAddresses[cluster], gatewayapiv1.GatewayStatusAddress(address))
 		}
 	}
 	if addressErr != nil {
@@ -250,6 +314,16 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	log.V(3).Info("allAddresses", "allAddresses", allAddresses)
 	upstreamGateway.Status.Addresses = allAddresses
 
+	clusterConditions := map[string][]metav1.Condition{}
+	for _, cluster := range clusters {
+		conditions, err := r.Placement.GetConditions(ctx, upstreamGateway, cluster)
+		if err != nil {
+			// May not have the status yet, the aggregated conditions will report it as unknown
+			log.V(3).Info("conditions unknown for cluster", "cluster", cluster, "message", err)
+		}
+		clusterConditions[cluster] = conditions
+	}
+
 	allListenerStatuses := []gatewayapiv1.ListenerStatus{}
 	specListeners := upstreamGateway.Spec.Listeners
 	for _, listener := range specListeners {
@@ -260,22 +334,151 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				log.Info("AttachedRoutes unknown for listener. Ignoring", "listener", listener.Name, "cluster", cluster, "message", err)
 				continue
 			}
+			listenerConditions, err := r.Placement.ListenerConditions(ctx, upstreamGateway, string(listener.Name), cluster)
+			if err != nil {
+				log.V(3).Info("conditions unknown for listener", "listener", listener.Name, "cluster", cluster, "message", err)
+			}
 			allListenerStatuses = append(allListenerStatuses, gatewayapiv1.ListenerStatus{
 				Name:           gatewayapiv1.SectionName(fmt.Sprintf("%s.%s", cluster, string(listener.Name))),
 				AttachedRoutes: int32(attachedRoutes),
 				SupportedKinds: []gatewayapiv1.RouteGroupKind{},
-				Conditions:     []metav1.Condition{},
+				Conditions:     buildListenerConditions(upstreamGateway.Generation, listenerConditions),
 			})
 		}
 	}
+	for _, listener := range specListeners {
+		resolvedRefs, invalid := invalidListeners[listener.Name]
+		if !invalid {
+			continue
+		}
+		// invalid listeners are not placed on any cluster so are reported once under the listener name
+		allListenerStatuses = append(allListenerStatuses, gatewayapiv1.ListenerStatus{
+			Name:           listener.Name,
+			SupportedKinds: []gatewayapiv1.RouteGroupKind{},
+			Conditions: []metav1.Condition{
+				resolvedRefs,
+				{
+					Type:               string(gatewayapiv1.ListenerConditionProgrammed),
+					Status:             metav1.ConditionFalse,
+					Reason:             string(gatewayapiv1.ListenerReasonInvalid),
+					Message:            "listener has invalid certificate references",
+					ObservedGeneration: upstreamGateway.Generation,
+				},
+			},
+		})
+	}
 	upstreamGateway.Status.Listeners = allListenerStatuses
 
-	acceptedCondition := buildAcceptedCondition(upstreamGateway.Generation, metav1.ConditionTrue)
-	programmedCondition := buildProgrammedCondition(upstreamGateway.Generation, clusters, programmedStatus, err)
+	// a ConfigMap of the same name created by a user is reported instead of being taken over
+	clusterStatusErr := r.reconcileClusterStatus(ctx, upstreamGateway, clusters, clusterAddresses, params)
+	if clusterStatusErr != nil && !errors.Is(clusterStatusErr, errConfigMapConflict) {
+		return ctrl.Result{}, clusterStatusErr
+	}
+	if clusterStatusCondition := buildClusterStatusPublishedCondition(upstreamGateway, clusterStatusErr); clusterStatusCondition != nil {
+		meta.SetStatusCondition(&upstreamGateway.Status.Conditions, *clusterStatusCondition)
+	} else {
+		meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, ClusterStatusPublishedConditionType)
+	}
+
+	acceptedCondition := aggregateAcceptedCondition(upstreamGateway.Generation, clusterConditions)
+	if len(invalidListeners) > 0 {
+		acceptedCondition = buildListenersNotValidCondition(upstreamGateway.Generation, acceptedCondition, len(specListeners), invalidListeners)
+	}
+	programmedCondition := aggregateProgrammedCondition(buildProgrammedCondition(upstreamGateway.Generation, clusters, programmedStatus, err), clusterConditions)
 
 	meta.SetStatusCondition(&upstreamGateway.Status.Conditions, acceptedCondition)
 	meta.SetStatusCondition(&upstreamGateway.Status.Conditions, programmedCondition)
 
+	certificates, err := r.gatewayCertificates(ctx, upstreamGateway)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get gateway certificates : %w", err)
+	}
+	if certificatesCondition := buildCertificatesReadyCondition(upstreamGateway.Generation, certificates); certificatesCondition != nil {
+		meta.SetStatusCondition(&upstreamGateway.Status.Conditions, *certificatesCondition)
+	} else {
+		meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, CertificatesReadyConditionType)
+	}
+
+	placedCertificates, err := r.placedCertificates(ctx, upstreamGateway, clusters, invalidListeners)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get placed certificates : %w", err)
+	}
+	recordCertificateMetrics(upstreamGateway, placedCertificates)
+	if syncedCondition := buildCertificatesSyncedCondition(upstreamGateway.Generation, placedCertificates); syncedCondition != nil {
+		meta.SetStatusCondition(&upstreamGateway.Status.Conditions, *syncedCondition)
+	} else {
+		meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, CertificatesSyncedConditionType)
+	}
+
+	// the capabilities and downstream classes are reported for the targeted clusters, which may not be placed yet
+	targets, err := r.Placement.GetClusters(ctx, upstreamGateway)
+	if err != nil {
+		log.V(3).Info("failed to get target clusters, reporting the downstream classes of the placed clusters", "error", err)
+	}
+	recordPlacementMetrics(upstreamGateway, targets.Len(), len(clusters))
+	requireKey := r.EncryptSecrets && placesSecrets(upstreamGateway, invalidListeners)
+	targetedCapabilities, err := r.targetedClusterCapabilities(ctx, sets.List(targets), params, requireKey)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get cluster capabilities : %w", err)
+	}
+	if capabilitiesCondition := buildClusterCapabilitiesCondition(upstreamGateway.Generation, targetedCapabilities); capabilitiesCondition != nil {
+		meta.SetStatusCondition(&upstreamGateway.Status.Conditions, *capabilitiesCondition)
+	} else {
+		meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, ClusterCapabilitiesConditionType)
+	}
+	// the clusters refused for lacking capabilities are only reported in the capabilities condition
+	refused := refusedClusters(targetedCapabilities)
+	classClusters := sets.New(clusters...).Union(targets).Delete(refused...)
+	downstreamClasses, err := r.placedDownstreamClasses(ctx, params, sets.List(classClusters))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get downstream classes : %w", err)
+	}
+	if classesCondition := buildDownstreamClassesAcceptedCondition(upstreamGateway.Generation, downstreamClasses); classesCondition != nil {
+		meta.SetStatusCondition(&upstreamGateway.Status.Conditions, *classesCondition)
+	} else {
+		meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, DownstreamClassesAcceptedConditionType)
+	}
+
+	// the assignment is reported as made while placing the gateway, it is left as last reported when the gateway
+	// could not be reconciled far enough to assign the addresses
+	if addresses != nil {
+		if addressesCondition := buildAddressesAssignedCondition(upstreamGateway.Generation, addresses, clusterConditions); addressesCondition != nil {
+			meta.SetStatusCondition(&upstreamGateway.Status.Conditions, *addressesCondition)
+		} else {
+			meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, AddressesAssignedConditionType)
+		}
+	}
+
+	if overridesCondition := buildClusterOverridesAppliedCondition(upstreamGateway.Generation, params, r.overrideRefusals.get(client.ObjectKeyFromObject(upstreamGateway))); overridesCondition != nil {
+		meta.SetStatusCondition(&upstreamGateway.Status.Conditions, *overridesCondition)
+	} else {
+		meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, ClusterOverridesAppliedConditionType)
+	}
+
+	if pausedCondition := buildPausedCondition(upstreamGateway); pausedCondition != nil {
+		meta.SetStatusCondition(&upstreamGateway.Status.Conditions, *pausedCondition)
+	} else {
+		meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, PausedConditionType)
+	}
+	planConflict := false
+	if isPlanning(upstreamGateway) {
+		if planConflict, err = r.planConflicts(ctx, upstreamGateway); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to get placement plan : %w", err)
+		}
+	}
+	if planCondition := buildPlanPublishedCondition(upstreamGateway, planConflict); planCondition != nil {
+		meta.SetStatusCondition(&upstreamGateway.Status.Conditions, *planCondition)
+	} else {
+		meta.RemoveStatusCondition(&upstreamGateway.Status.Conditions, PlanPublishedConditionType)
+	}
+
+	now := time.Now()
+	expiring := expiringCertificates(placedCertificates, now, r.CertificateExpiryWarning)
+	for _, certificate := range r.expiryWarnings.entered(client.ObjectKeyFromObject(upstreamGateway), expiring) {
+		r.eventf(upstreamGateway, corev1.EventTypeWarning, CertificateExpiringReason,
+			"certificate in secret %s on cluster %s expires %s", certificate.secret, certificate.cluster, certificate.notAfter.UTC().Format(time.RFC3339))
+	}
+
 	if !isDeleting(upstreamGateway) && !reflect.DeepEqual(upstreamGateway.Status, previous.Status) {
 		return reconcile.Result{}, r.Status().Update(ctx, upstreamGateway)
 	}
@@ -284,7 +487,8 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		log.V(3).Info("requeuing gateway in ", "namespace", upstreamGateway.Namespace, "with name", upstreamGateway.Name)
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Second * 10}, reconcileErr
 	}
-	return ctrl.Result{}, reconcileErr
+	// revisit the gateway when the next placed certificate enters the expiry warning window
+	return ctrl.Result{RequeueAfter: nextExpiryWarning(placedCertificates, now, r.CertificateExpiryWarning)}, reconcileErr
 }
 
 // reconcileClusterLabels fetches labels from ManagedCluster related to clusters array and adds them to the provided Gateway
@@ -315,11 +519,14 @@ func (r *GatewayReconciler) reconcileClusterLabels(ctx context.Context, gateway
 }
 
 // reconcileDownstreamGateway takes the upstream definition and transforms it as needed to apply it to the downstream spokes
-func (r *GatewayReconciler) reconcileDownstreamFromUpstreamGateway(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway, params *Params) (bool, metav1.ConditionStatus, []string, error) {
+func (r *GatewayReconciler) reconcileDownstreamFromUpstreamGateway(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway, params *Params) (bool, metav1.ConditionStatus, []string, map[gatewayapiv1.SectionName]metav1.Condition, *addressAssignment, error) {
 	log := crlog.FromContext(ctx)
 	clusters := []string{}
 	downstream := upstreamGateway.DeepCopy()
-	downstreamNS := fmt.Sprintf("%s-%s", "kuadrant", downstream.Namespace)
+	downstreamNS, err := params.GetDownstreamNamespace(upstreamGateway)
+	if err != nil {
+		return false, metav1.ConditionFalse, clusters, nil, nil, err
+	}
 	downstream.Status = gatewayapiv1.GatewayStatus{}
 
 	// reset this for the sync as we don't want control plane level UID, creation etc etc
@@ -329,90 +536,254 @@ func (r *GatewayReconciler) reconcileDownstreamFromUpstreamGateway(ctx context.C
 		Labels:      downstream.Labels,
 		Annotations: downstream.Annotations,
 	}
+	params.ApplyDownstreamMetadata(downstream)
 	if downstream.Labels == nil {
 		downstream.Labels = map[string]string{}
 	}
 	downstream.Labels[ManagedLabel] = "true"
+	if !params.HasManagedDownstreamNamespace() {
+		metadata.AddAnnotation(downstream, placement.UnmanagedNamespaceAnnotation, "true")
+	}
 	if isDeleting(upstreamGateway) {
 		log.Info("deleting downstream gateways owned by upstream gateway ", "name", downstream.Name, "namespace", downstream.Namespace)
-		targets, err := r.Placement.Place(ctx, upstreamGateway, downstream)
+		targets, err := r.Placement.Place(ctx, upstreamGateway, downstream, nil)
 		if err != nil {
-			return false, metav1.ConditionFalse, clusters, err
+			return false, metav1.ConditionFalse, clusters, nil, nil, err
 		}
-		return false, metav1.ConditionTrue, targets.UnsortedList(), nil
+		return false, metav1.ConditionTrue, targets.UnsortedList(), nil, nil, nil
 	}
 
 	if len(upstreamGateway.Spec.Listeners) == 0 {
-		return false, metav1.ConditionFalse, clusters, fmt.Errorf("no managed listeners found")
+		return false, metav1.ConditionFalse, clusters, nil, nil, fmt.Errorf("no managed listeners found")
 	}
 
 	// get tls secrets for all TLS listeners.
-	tlsSecrets, err := r.getTLSSecrets(ctx, upstreamGateway, downstream)
+	tlsSecrets, invalidListeners, err := r.getTLSSecrets(ctx, upstreamGateway, downstream, params)
 	if err != nil {
-		return true, metav1.ConditionFalse, clusters, fmt.Errorf("failed to get tls secrets : %s", err)
+		return true, metav1.ConditionFalse, clusters, nil, nil, fmt.Errorf("failed to get tls secrets : %s", err)
 	}
+	// only the valid listeners are placed, the invalid ones are reported in the upstream listener status
+	downstream.Spec.Listeners = slice.Filter(downstream.Spec.Listeners, func(listener gatewayapiv1.Listener) bool {
+		_, invalid := invalidListeners[listener.Name]
+		return !invalid
+	})
 
 	// some of this should be pulled from gateway class params
 	if params != nil {
 		if err := r.reconcileParams(ctx, downstream, params); err != nil {
-			return false, metav1.ConditionUnknown, clusters, fmt.Errorf("failed to get reconcileParams : %s", err)
+			return false, metav1.ConditionUnknown, clusters, invalidListeners, nil, fmt.Errorf("failed to get reconcileParams : %s", err)
 		}
 	}
 
-	// ensure the gateways are placed into the right target clusters and removed from any that are no longer targeted
-	targets, err := r.Placement.Place(ctx, upstreamGateway, downstream, tlsSecrets...)
+	if !params.PropagatesInfrastructure() {
+		downstream.Spec.Infrastructure = nil
+	}
+	// the addresses are assigned to the targeted clusters able to run the gateway. The pool address of each cluster
+	// is recorded on the upstream gateway so that it is kept when other clusters are added or removed
+	assignable, err := r.assignableClusters(ctx, upstreamGateway, params, r.EncryptSecrets && len(tlsSecrets) > 0)
 	if err != nil {
-		return true, metav1.ConditionFalse, clusters, fmt.Errorf("failed to place gateway : %w", err)
+		return true, metav1.ConditionFalse, clusters, invalidListeners, nil, fmt.Errorf("failed to get the clusters to assign addresses to : %w", err)
+	}
+	addresses := params.assignAddresses(upstreamGateway, assignable)
+
+	// the clusters refused for their overrides are reported in the upstream status. They are worked out for the
+	// targeted clusters on every reconcile, including when the gateway is left as placed or removed
+	overridesRefused := map[string]string{}
+	defer r.overrideRefusals.set(client.ObjectKeyFromObject(upstreamGateway), overridesRefused)
+	customise := r.clusterCustomiser(params, addresses, overridesRefused)
+	if len(downstream.Spec.Listeners) == 0 || isPaused(upstreamGateway) {
+		if err := r.customiseTargets(ctx, upstreamGateway, downstream, customise); err != nil {
+			return true, metav1.ConditionFalse, clusters, invalidListeners, addresses, fmt.Errorf("failed to apply cluster overrides : %w", err)
+		}
+	}
+
+	if len(downstream.Spec.Listeners) == 0 {
+		// a paused or planned gateway is left as placed
+		if isPaused(upstreamGateway) || isPlanning(upstreamGateway) {
+			log.Info("no valid listeners to place", "gateway", upstreamGateway.Name, "namespace", upstreamGateway.Namespace)
+			placed, err := r.Placement.GetPlacedClusters(ctx, upstreamGateway)
+			if err != nil {
+				return false, metav1.ConditionUnknown, clusters, invalidListeners, addresses, fmt.Errorf("failed to get placed clusters : %s", err)
+			}
+			return false, metav1.ConditionFalse, sets.List(placed), invalidListeners, addresses, nil
+		}
+		// the gateway is removed rather than left with the listeners and secrets that are no longer valid, such as
+		// a secret whose ReferenceGrant was revoked
+		log.Info("no valid listeners to place, removing gateway from its clusters", "gateway", upstreamGateway.Name, "namespace", upstreamGateway.Namespace)
+		remaining, err := r.Placement.Remove(ctx, upstreamGateway)
+		if err != nil {
+			return true, metav1.ConditionFalse, sets.List(remaining), invalidListeners, addresses, fmt.Errorf("failed to remove gateway : %w", err)
+		}
+		return false, metav1.ConditionFalse, clusters, invalidListeners, addresses, nil
+	}
+
+	// a paused gateway is left as placed, its status is still reported
+	if isPaused(upstreamGateway) {
+		requeue, programmedStatus, placed, invalid, err := r.pausedDownstream(ctx, upstreamGateway, invalidListeners)
+		return requeue, programmedStatus, placed, invalid, addresses, err
+	}
+
+	// a planned gateway is left as placed and the changes placing it would make are published for review
+	if isPlanning(upstreamGateway) {
+		requeue, programmedStatus, placed, invalid, err := r.planDownstream(ctx, upstreamGateway, downstream, customise, tlsSecrets, invalidListeners)
+		return requeue, programmedStatus, placed, invalid, addresses, err
+	}
+	if err := r.deletePlan(ctx, upstreamGateway); err != nil {
+		return true, metav1.ConditionFalse, clusters, invalidListeners, addresses, fmt.Errorf("failed to delete placement plan : %w", err)
+	}
+	// the assignment is only recorded once the gateway is placed, a planned assignment is published in the plan
+	pooled, err := addresses.pooledAnnotation()
+	if err != nil {
+		return false, metav1.ConditionFalse, clusters, invalidListeners, addresses, err
+	}
+	if pooled != "" {
+		metadata.AddAnnotation(upstreamGateway, AssignedAddressesAnnotation, pooled)
+	} else {
+		metadata.RemoveAnnotation(upstreamGateway, AssignedAddressesAnnotation)
+	}
+
+	// ensure the gateways are placed into the right target clusters and removed from any that are no longer targeted.
+	// The addresses and cluster overrides are applied to the gateway placed on each cluster
+	targets, err := r.Placement.Place(ctx, upstreamGateway, downstream, customise, tlsSecrets...)
+	if err != nil {
+		return true, metav1.ConditionFalse, clusters, invalidListeners, addresses, fmt.Errorf("failed to place gateway : %w", err)
 	}
 
 	log.Info("Gateway Placed ", "gateway", upstreamGateway.Name, "namespace", upstreamGateway.Namespace, "targets", targets.UnsortedList())
 	//get updated list of clusters where this gateway has been successfully placed
 	placed, err := r.Placement.GetPlacedClusters(ctx, upstreamGateway)
 	if err != nil {
-		return false, metav1.ConditionUnknown, targets.UnsortedList(), fmt.Errorf("failed to get placed clusters : %s", err)
+		return false, metav1.ConditionUnknown, targets.UnsortedList(), invalidListeners, addresses, fmt.Errorf("failed to get placed clusters : %s", err)
 	}
 	//update the cluster set, needs to be ordered or the status update can continually change and cause spurious updates
 	clusters = sets.List(placed)
 	if placed.Equal(targets) && placed.Len() > 0 {
-		return false, metav1.ConditionTrue, clusters, nil
+		return false, metav1.ConditionTrue, clusters, invalidListeners, addresses, nil
 	}
 	log.Info("Gateway Reconciled Successfully ", "gateway", upstreamGateway.Name, "namespace", upstreamGateway.Namespace)
-	return false, metav1.ConditionUnknown, clusters, nil
+	return false, metav1.ConditionUnknown, clusters, invalidListeners, addresses, nil
 }
 
-func (r *GatewayReconciler) getTLSSecrets(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway, downstreamGateway *gatewayapiv1.Gateway) ([]metav1.Object, error) {
+// getTLSSecrets returns the downstream copies of the TLS secrets referenced by the listeners of the gateway.
+// Listeners referencing a missing or invalid secret, or a secret in another namespace that no ReferenceGrant
+// permits, are returned with a ResolvedRefs=False condition and their secrets are not included in the returned list.
+// The metadata of the downstream secrets is filtered and injected according to the params
+func (r *GatewayReconciler) getTLSSecrets(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway, downstreamGateway *gatewayapiv1.Gateway, params *Params) ([]metav1.Object, map[gatewayapiv1.SectionName]metav1.Condition, error) {
 	log := crlog.FromContext(ctx)
 	tlsSecrets := []metav1.Object{}
-	var listenerTLSErr error
+	invalidListeners := map[gatewayapiv1.SectionName]metav1.Condition{}
 	for _, listener := range upstreamGateway.Spec.Listeners {
-		if listener.TLS != nil {
-			for _, secretRef := range listener.TLS.CertificateRefs {
-				ns := upstreamGateway.GetNamespace()
-				if secretRef.Namespace != nil {
-					ns = string(*secretRef.Namespace)
+		if listener.TLS == nil {
+			continue
+		}
+		listenerSecrets := []metav1.Object{}
+		for _, secretRef := range listener.TLS.CertificateRefs {
+			ns := upstreamGateway.GetNamespace()
+			if secretRef.Namespace != nil {
+				ns = string(*secretRef.Namespace)
+			}
+			permitted, err := secretRefPermitted(ctx, r.Client, upstreamGateway, ns, string(secretRef.Name))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to check reference grants for listener %s %w", listener.Name, err)
+			}
+			if !permitted {
+				log.V(3).Info("tls secret reference not permitted", "listener", listener.Name, "secret", fmt.Sprintf("%s/%s", ns, secretRef.Name))
+				invalidListeners[listener.Name] = metav1.Condition{
+					Type:               string(gatewayapiv1.ListenerConditionResolvedRefs),
+					Status:             metav1.ConditionFalse,
+					Reason:             string(gatewayapiv1.ListenerReasonRefNotPermitted),
+					Message:            fmt.Sprintf("secret %s/%s is not permitted by any ReferenceGrant", ns, secretRef.Name),
+					ObservedGeneration: upstreamGateway.Generation,
 				}
-				tlsSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
-					Name:      string(secretRef.Name),
-					Namespace: ns,
-				}}
-				if err := r.Client.Get(ctx, client.ObjectKeyFromObject(tlsSecret), tlsSecret); err != nil {
-					log.Error(err, "cant find tls secret")
-					listenerTLSErr = errors.Join(fmt.Errorf("failed to find tls secret for listener %s %w", listener.Name, err))
-					continue
+				break
+			}
+			tlsSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name:      string(secretRef.Name),
+				Namespace: ns,
+			}}
+			if err := r.Client.Get(ctx, client.ObjectKeyFromObject(tlsSecret), tlsSecret); err != nil {
+				if !k8serrors.IsNotFound(err) {
+					return nil, nil, fmt.Errorf("failed to get tls secret for listener %s %w", listener.Name, err)
 				}
+				log.V(3).Info("tls secret not found", "listener", listener.Name, "secret", client.ObjectKeyFromObject(tlsSecret))
+				resolvedRefs := buildResolvedRefsCondition(upstreamGateway.Generation, fmt.Sprintf("secret %s/%s not found", ns, secretRef.Name))
+				// the event is emitted when the listener turns invalid rather than on every reconcile
+				if !listenerReports(upstreamGateway, listener.Name, resolvedRefs) {
+					r.eventf(upstreamGateway, corev1.EventTypeWarning, TLSSecretMissingReason, "secret %s/%s of listener %s not found", ns, secretRef.Name, listener.Name)
+				}
+				invalidListeners[listener.Name] = resolvedRefs
+				break
+			}
+			certificate, err := getSecretCertificate(ctx, r.Client, tlsSecret)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get certificate for listener %s %w", listener.Name, err)
+			}
+			// wait for cert-manager to issue the certificate before placing the listener. Once issued the secret keeps
+			// the last issued certificate, so a renewal in progress does not remove the listener from the downstream
+			if certificate != nil && !isCertificateReady(certificate) && !isCertificateIssued(certificate) {
+				log.V(3).Info("waiting for certificate to be ready", "listener", listener.Name, "certificate", client.ObjectKeyFromObject(certificate))
+				invalidListeners[listener.Name] = buildResolvedRefsCondition(upstreamGateway.Generation, fmt.Sprintf("waiting for certificate %s/%s to be ready", certificate.Namespace, certificate.Name))
+				break
+			}
+			if err := validateTLSSecret(tlsSecret); err != nil {
+				log.V(3).Info("tls secret is invalid", "listener", listener.Name, "secret", client.ObjectKeyFromObject(tlsSecret), "error", err)
+				invalidListeners[listener.Name] = buildResolvedRefsCondition(upstreamGateway.Generation, fmt.Sprintf("secret %s/%s is invalid: %s", ns, secretRef.Name, err))
+				break
+			}
 
-				downstreamSecret := tlsSecret.DeepCopy()
-				downstreamSecret.ObjectMeta = metav1.ObjectMeta{}
-				downstreamSecret.Name = tlsSecret.Name
-				downstreamSecret.Namespace = downstreamGateway.Namespace
-				downstreamSecret.Labels = tlsSecret.Labels
-				downstreamSecret.Annotations = tlsSecret.Annotations
-
-				tlsSecrets = append(tlsSecrets, downstreamSecret)
+			downstreamSecret := tlsSecret.DeepCopy()
+			downstreamSecret.ObjectMeta = metav1.ObjectMeta{}
+			downstreamSecret.Name = tlsSecret.Name
+			downstreamSecret.Namespace = downstreamGateway.Namespace
+			downstreamSecret.Labels = tlsSecret.Labels
+			downstreamSecret.Annotations = tlsSecret.Annotations
+			params.ApplyDownstreamMetadata(downstreamSecret)
+			if err := addCertificateAnnotations(downstreamSecret); err != nil {
+				log.V(3).Info("tls secret certificate is invalid", "listener", listener.Name, "secret", client.ObjectKeyFromObject(tlsSecret), "error", err)
+				invalidListeners[listener.Name] = buildResolvedRefsCondition(upstreamGateway.Generation, fmt.Sprintf("secret %s/%s is invalid: %s", ns, secretRef.Name, err))
+				break
 			}
+
+			listenerSecrets = append(listenerSecrets, downstreamSecret)
+		}
+		if _, invalid := invalidListeners[listener.Name]; !invalid {
+			tlsSecrets = append(tlsSecrets, listenerSecrets...)
 		}
 	}
-	return tlsSecrets, listenerTLSErr
+	return tlsSecrets, invalidListeners, nil
+}
+
+// validateTLSSecret checks the secret is a kubernetes.io/tls secret holding a parseable certificate and key
+func validateTLSSecret(secret *corev1.Secret) error {
+	if secret.Type != corev1.SecretTypeTLS {
+		return fmt.Errorf("expected secret type %s but got %s", corev1.SecretTypeTLS, secret.Type)
+	}
+	if _, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// listenerReports returns whether the status of the gateway already reports the condition for the invalid listener
+func listenerReports(gateway *gatewayapiv1.Gateway, listenerName gatewayapiv1.SectionName, condition metav1.Condition) bool {
+	for _, listener := range gateway.Status.Listeners {
+		if listener.Name != listenerName {
+			continue
+		}
+		reported := meta.FindStatusCondition(listener.Conditions, condition.Type)
+		return reported != nil && reported.Status == condition.Status && reported.Message == condition.Message
+	}
+	return false
+}
+
+func buildResolvedRefsCondition(generation int64, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(gatewayapiv1.ListenerConditionResolvedRefs),
+		Status:             metav1.ConditionFalse,
+		Reason:             string(gatewayapiv1.ListenerReasonInvalidCertificateRef),
+		Message:            message,
+		ObservedGeneration: generation,
+	}
 }
 
 func (r *GatewayReconciler) reconcileParams(ctx context.Context, gateway *gatewayapiv1.Gateway, params *Params) error {
@@ -424,7 +795,7 @@ func (r *GatewayReconciler) reconcileParams(ctx context.Context, gateway *gatewa
 
 	gateway.Spec.GatewayClassName = gatewayapiv1.ObjectName(downstreamClass)
 
-	policiesToSync := slice.Map(params.PoliciesToSync, ParamsGroupVersionResource.ToGroupVersionResource)
+	policiesToSync := slice.Map(params.PoliciesToSync, policyGroupVersionResource)
 
 	for _, gvr := range policiesToSync {
 		// If it's already watched skip it
@@ -441,7 +812,8 @@ func (r *GatewayReconciler) reconcileParams(ctx context.Context, gateway *gatewa
 			GVR:           gvr,
 			Client:        r.Client,
 			DynamicClient: r.DynamicClient,
-			Gateway:       gateway,
+			Downstream:    r.policyDownstream,
+			Metadata:      r.policyMetadata,
 			Syncer:        &policysync.FakeSyncer{},
 		}
 		informer := r.PolicyInformersManager.InformerFactory.ForResource(gvr).Informer()
@@ -482,6 +854,55 @@ func (r *GatewayReconciler) reconcileParams(ctx context.Context, gateway *gatewa
 	return nil
 }
 
+// policyDownstream resolves the gateway targeted by the policy from its targetRef and the downstream gateway the
+// policy is synced with. The handler of a policy resource is shared by the gateways of every class syncing it, so
+// the gateway is resolved for each event rather than when the handler is registered
+func (r *GatewayReconciler) policyDownstream(ctx context.Context, policy policysync.Policy) (*gatewayapiv1.Gateway, *gatewayapiv1.Gateway, error) {
+	upstream, params, err := r.policyTarget(ctx, policy)
+	if err != nil || upstream == nil {
+		return nil, nil, err
+	}
+	namespace, err := params.GetDownstreamNamespace(upstream)
+	if err != nil {
+		return nil, nil, err
+	}
+	downstream := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: upstream.Name, Namespace: namespace}}
+	return upstream, downstream, nil
+}
+
+// policyMetadata applies the downstream metadata rules of the params of the class of the gateway targeted by the
+// policy. The default rules apply when the policy doesn't target a gateway placed by the controller
+func (r *GatewayReconciler) policyMetadata(ctx context.Context, policy policysync.Policy) error {
+	_, params, err := r.policyTarget(ctx, policy)
+	if err != nil {
+		return err
+	}
+	params.ApplyDownstreamMetadata(policy)
+	return nil
+}
+
+// policyTarget returns the gateway targeted by the policy and the params of its class. A nil gateway is returned
+// when the policy doesn't target a gateway of a class handled by the controller
+func (r *GatewayReconciler) policyTarget(ctx context.Context, policy policysync.Policy) (*gatewayapiv1.Gateway, *Params, error) {
+	target, ok := policysync.TargetedGateway(policy)
+	if !ok {
+		return nil, nil, nil
+	}
+	gateway := &gatewayapiv1.Gateway{}
+	if err := r.Client.Get(ctx, target, gateway); err != nil {
+		return nil, nil, client.IgnoreNotFound(err)
+	}
+	managed, err := isManagedGatewayClass(ctx, r.Client, string(gateway.Spec.GatewayClassName))
+	if err != nil || !managed {
+		return nil, nil, err
+	}
+	params, err := getParams(ctx, r.Client, string(gateway.Spec.GatewayClassName))
+	if err != nil {
+		return nil, nil, err
+	}
+	return gateway, params, nil
+}
+
 func buildProgrammedCondition(generation int64, placed []string, programmedStatus metav1.ConditionStatus, err error) metav1.Condition {
 	var reason = gatewayapiv1.GatewayReasonProgrammed
 	message := "waiting for gateway to placed on clusters %v"
@@ -526,7 +947,21 @@ func buildAcceptedCondition(generation int64, acceptedStatus metav1.ConditionSta
 func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager, ctx context.Context) error {
 	log := crlog.FromContext(ctx)
 	clusterEventMapper := NewClusterEventMapper(log, mgr.GetClient())
-	//TODO need to trigger gateway reconcile when gatewayclass params changes
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &gatewayapiv1.Gateway{}, GatewaySecretIndex, gatewaySecretKeys); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &gatewayapiv1.Gateway{}, GatewayClustersIndex, gatewayPlacedClusters); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &gatewayapiv1.Gateway{}, GatewayPlacementIndex, gatewayPlacement); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &gatewayapiv1.Gateway{}, GatewayClassNameIndex, gatewayClassName); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &gatewayapiv1.GatewayClass{}, GatewayClassParamsIndex, gatewayClassParams); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&gatewayapiv1.Gateway{}).
 		Watches(&workv1.ManifestWork{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
@@ -549,36 +984,49 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager, ctx context.Conte
 			})
 			return requests
 		}), builder.OnlyMetadata).
-		Watches(&clusterv1beta2.PlacementDecision{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
-			// kinda want to get the old and new object here and only queue if the clusters have changed
-			// queue up gateways in this namespace
-			log.V(3).Info("enqueuing gateways based on placementdecision change ", " namespace", o.GetNamespace())
-			req := []reconcile.Request{}
-			l := &gatewayapiv1.GatewayList{}
-			if err := mgr.GetClient().List(ctx, l, &client.ListOptions{Namespace: o.GetNamespace()}); err != nil {
-				log.Error(err, "failed to list gateways to requeue")
-				return req
-			}
-			for _, g := range l.Items {
-				req = append(req, reconcile.Request{
-					NamespacedName: client.ObjectKeyFromObject(&g),
-				})
-			}
-			return req
-		})).
+		Watches(&workv1.ManifestWork{}, placement.ApplyFailureHandler()).
+		Watches(
+			&clusterv1beta2.PlacementDecision{},
+			handler.EnqueueRequestsFromMapFunc(r.placementDecisionToGateways),
+			builder.WithPredicates(placementDecisionChangedPredicate()),
+		).
+		Watches(
+			&gatewayapiv1.GatewayClass{},
+			handler.EnqueueRequestsFromMapFunc(r.gatewayClassToGateways),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.paramsToGateways)).
+		Watches(&v1alpha1.GatewayClassParameters{}, handler.EnqueueRequestsFromMapFunc(r.paramsToGateways)).
 		Watches(&corev1.Secret{}, &ClusterEventHandler{client: r.Client}).
+		Watches(&gatewayapiv1beta1.ReferenceGrant{}, handler.EnqueueRequestsFromMapFunc(r.referenceGrantToGateways)).
+		Watches(&certmanv1.Certificate{}, handler.EnqueueRequestsFromMapFunc(r.certificateToGateways)).
 		Watches(
 			&clusterv1.ManagedCluster{},
 			handler.EnqueueRequestsFromMapFunc(clusterEventMapper.MapToGateway),
+			builder.WithPredicates(managedClusterChangedPredicate()),
 		).
-		WithEventFilter(predicate.NewPredicateFuncs(func(object client.Object) bool {
-			gateway, ok := object.(*gatewayapiv1.Gateway)
-			if ok {
-				shouldReconcile := slice.ContainsString(getSupportedClasses(), string(gateway.Spec.GatewayClassName))
-				log.V(3).Info(" should reconcile", "gateway", gateway.Name, "with class ", gateway.Spec.GatewayClassName, "should ", shouldReconcile)
-				return slice.ContainsString(getSupportedClasses(), string(gateway.Spec.GatewayClassName))
-			}
-			return true
-		})).
+		WithEventFilter(managedGatewayPredicate(ctx, r.Client)).
 		Complete(r)
 }
+
+// managedGatewayPredicate filters out the events of the gateways of classes not handled by this controller. The
+// gateways placed by the controller are let through until their finalizer is removed, even once their class is
+// deleted or handled by another controller
+func managedGatewayPredicate(ctx context.Context, c client.Reader) predicate.Predicate {
+	log := crlog.FromContext(ctx)
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		gateway, ok := object.(*gatewayapiv1.Gateway)
+		if !ok {
+			return true
+		}
+		if isDeleting(gateway) || controllerutil.ContainsFinalizer(gateway, GatewayFinalizer) {
+			return true
+		}
+		shouldReconcile, err := isManagedGatewayClass(ctx, c, string(gateway.Spec.GatewayClassName))
+		if err != nil {
+			log.Error(err, "failed to get gateway class", "gateway", gateway.Name, "gatewayclass", gateway.Spec.GatewayClassName)
+		}
+		log.V(3).Info(" should reconcile", "gateway", gateway.Name, "with class ", gateway.Spec.GatewayClassName, "should ", shouldReconcile)
+		return shouldReconcile
+	})
+}
@@ -4,20 +4,34 @@ package gateway
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workv1 "open-cluster-management.io/api/work/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 	fakeplacement "github.com/Kuadrant/multicluster-gateway-controller/pkg/placement/fake"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/policysync"
 	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
 )
 
@@ -34,6 +48,8 @@ func TestGatewayReconciler_Reconcile(t *testing.T) {
 		fields fields
 		args   args
 		verify func(res ctrl.Result, err error, t *testing.T)
+		// wantEventReason is the reason of the event expected to be recorded, if any
+		wantEventReason string
 	}{
 		{
 			name: "gateway reconciled and updated",
@@ -67,6 +83,9 @@ func TestGatewayReconciler_Reconcile(t *testing.T) {
 								ObjectMeta: v1.ObjectMeta{
 									Name: testutil.DummyCRName,
 								},
+								Spec: gatewayapiv1.GatewayClassSpec{
+									ControllerName: ControllerName,
+								},
 							},
 						},
 					},
@@ -196,6 +215,7 @@ func TestGatewayReconciler_Reconcile(t *testing.T) {
 									Name: testutil.DummyCRName,
 								},
 								Spec: gatewayapiv1.GatewayClassSpec{
+									ControllerName: ControllerName,
 									ParametersRef: &gatewayapiv1.ParametersReference{
 										Group: "boop",
 										Kind:  "theCat",
@@ -210,18 +230,31 @@ func TestGatewayReconciler_Reconcile(t *testing.T) {
 			args: args{
 				req: testutil.BuildValidTestRequest(testutil.DummyCRName, testutil.Namespace),
 			},
-			verify: testutil.AssertNoErrorReconciliation(),
+			verify:          testutil.AssertNoErrorReconciliation(),
+			wantEventReason: InvalidParamsReason,
 		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
 			r := &GatewayReconciler{
 				Client:    testCase.fields.Client,
 				Scheme:    testCase.fields.Scheme,
 				Placement: fakeplacement.NewTestGatewayPlacer(),
+				Recorder:  recorder,
 			}
 			res, err := r.Reconcile(context.TODO(), testCase.args.req)
 			testCase.verify(res, err, t)
+			if testCase.wantEventReason != "" {
+				select {
+				case event := <-recorder.Events:
+					if !strings.Contains(event, testCase.wantEventReason) {
+						t.Errorf("expected a %s event but got %s", testCase.wantEventReason, event)
+					}
+				default:
+					t.Errorf("expected a %s event but got none", testCase.wantEventReason)
+				}
+			}
 		})
 	}
 }
@@ -299,6 +332,54 @@ func TestGatewayReconciler_reconcileDownstreamFromUpstreamGateway(t *testing.T)
 			wantRequeue:  false,
 			wantErr:      false,
 		},
+		{
+			name: "paused gateway left as placed",
+			fields: fields{
+				Client: testutil.GetValidTestClient(
+					getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace),
+				),
+				Scheme: testutil.GetValidTestScheme(),
+			},
+			args: args{
+				gateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Labels:      getTestGatewayLabels(),
+						Annotations: map[string]string{PausedAnnotation: "true"},
+						Namespace:   testutil.Namespace,
+						Name:        testutil.DummyCRName,
+					},
+					Spec: buildValidTestGatewaySpec(),
+				},
+			},
+			wantStatus:   v1.ConditionUnknown,
+			wantClusters: []string{testutil.Cluster},
+			wantRequeue:  false,
+			wantErr:      false,
+		},
+		{
+			name: "planned gateway left as placed",
+			fields: fields{
+				Client: testutil.GetValidTestClient(
+					getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace),
+				),
+				Scheme: testutil.GetValidTestScheme(),
+			},
+			args: args{
+				gateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Labels:      getTestGatewayLabels(),
+						Annotations: map[string]string{PlanAnnotation: "true"},
+						Namespace:   testutil.Namespace,
+						Name:        testutil.DummyCRName,
+					},
+					Spec: buildValidTestGatewaySpec(),
+				},
+			},
+			wantStatus:   v1.ConditionTrue,
+			wantClusters: []string{testutil.Cluster},
+			wantRequeue:  false,
+			wantErr:      false,
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -307,7 +388,7 @@ func TestGatewayReconciler_reconcileDownstreamFromUpstreamGateway(t *testing.T)
 				Scheme:    testCase.fields.Scheme,
 				Placement: fakeplacement.NewTestGatewayPlacer(),
 			}
-			requeue, programmedStatus, clusters, err := r.reconcileDownstreamFromUpstreamGateway(context.TODO(), testCase.args.gateway, &Params{})
+			requeue, programmedStatus, clusters, _, _, err := r.reconcileDownstreamFromUpstreamGateway(context.TODO(), testCase.args.gateway, &Params{})
 			if (err != nil) != testCase.wantErr || !testutil.GotExpectedError(testCase.expectedError, err) {
 				t.Errorf("reconcileGateway() error = %v, wantErr %v, expectedError %v", err, testCase.wantErr, testCase.expectedError)
 			}
@@ -324,7 +405,126 @@ func TestGatewayReconciler_reconcileDownstreamFromUpstreamGateway(t *testing.T)
 	}
 }
 
+func TestPlannedGatewayAssignedAddresses(t *testing.T) {
+	params := &Params{Addresses: &v1alpha1.AddressAssignment{Mode: string(PoolAddresses)}}
+	for _, planned := range []bool{true, false} {
+		gateway := &gatewayapiv1.Gateway{
+			ObjectMeta: v1.ObjectMeta{
+				Labels:      getTestGatewayLabels(),
+				Annotations: map[string]string{AssignedAddressesAnnotation: `{"c1":"10.0.0.1"}`},
+				Namespace:   testutil.Namespace,
+				Name:        testutil.DummyCRName,
+			},
+			Spec: buildValidTestGatewaySpec(),
+		}
+		if planned {
+			gateway.Annotations[PlanAnnotation] = "true"
+		}
+		r := &GatewayReconciler{
+			Client:    testutil.GetValidTestClient(getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace)),
+			Scheme:    testutil.GetValidTestScheme(),
+			Placement: fakeplacement.NewTestGatewayPlacer(),
+		}
+		if _, _, _, _, _, err := r.reconcileDownstreamFromUpstreamGateway(context.TODO(), gateway, params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// the fake placement targets no cluster, so the placed gateway no longer has an assignment
+		_, assigned := gateway.Annotations[AssignedAddressesAnnotation]
+		if assigned != planned {
+			t.Errorf("expected the assignment to be kept only on the planned gateway, planned %v assigned %v", planned, assigned)
+		}
+	}
+}
+
+func TestRevokedReferenceGrantRemovesSecret(t *testing.T) {
+	secretNamespace := "certs"
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    getTestGatewayLabels(),
+			Namespace: testutil.Namespace,
+			Name:      testutil.DummyCRName,
+		},
+		Spec: buildValidTestGatewaySpec(),
+	}
+	gateway.Spec.Listeners[0].TLS = &gatewayapiv1.GatewayTLSConfig{
+		CertificateRefs: []gatewayapiv1.SecretObjectReference{{
+			Name:      testutil.TLSSecretName,
+			Namespace: testutil.Pointer(gatewayapiv1.Namespace(secretNamespace)),
+		}},
+	}
+	grant := &gatewayapiv1beta1.ReferenceGrant{
+		ObjectMeta: v1.ObjectMeta{Name: "grant", Namespace: secretNamespace},
+		Spec: gatewayapiv1beta1.ReferenceGrantSpec{
+			From: []gatewayapiv1beta1.ReferenceGrantFrom{{Group: gatewayapiv1.GroupName, Kind: "Gateway", Namespace: testutil.Namespace}},
+			To:   []gatewayapiv1beta1.ReferenceGrantTo{{Group: "", Kind: "Secret"}},
+		},
+	}
+	decision := &clusterv1beta1.PlacementDecision{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      testutil.Placement + "-decision-1",
+			Namespace: testutil.Namespace,
+			Labels:    map[string]string{placement.OCMPlacementLabel: testutil.Placement},
+		},
+		Status: clusterv1beta1.PlacementDecisionStatus{Decisions: []clusterv1beta1.ClusterDecision{{ClusterName: testutil.Cluster}}},
+	}
+	scheme := testutil.GetValidTestScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = clusterv1beta1.AddToScheme(scheme)
+	_ = workv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(grant, decision, &clusterv1.ManagedCluster{ObjectMeta: v1.ObjectMeta{Name: testutil.Cluster}}).
+		WithLists(getValidTLSCertificateSecretList(testutil.TLSSecretName, secretNamespace)).
+		Build()
+	r := &GatewayReconciler{
+		Client:    c,
+		Scheme:    scheme,
+		Placement: placement.NewOCMPlacer(c),
+	}
+	work := &workv1.ManifestWork{ObjectMeta: v1.ObjectMeta{Name: placement.WorkName(gateway), Namespace: testutil.Cluster}}
+
+	if _, _, _, _, _, err := r.reconcileDownstreamFromUpstreamGateway(context.TODO(), gateway, &Params{}); err != nil {
+		t.Fatalf("unexpected error placing the gateway: %v", err)
+	}
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(work), work); err != nil {
+		t.Fatalf("expected the gateway to be placed: %v", err)
+	}
+	if !workCarriesSecret(work) {
+		t.Fatalf("expected the work to carry the secret of the listener")
+	}
+
+	// revoking the grant of the only listener leaves no valid listener to place
+	if err := c.Delete(context.TODO(), grant); err != nil {
+		t.Fatalf("unexpected error revoking the grant: %v", err)
+	}
+	if _, _, _, _, _, err := r.reconcileDownstreamFromUpstreamGateway(context.TODO(), gateway, &Params{}); err != nil {
+		t.Fatalf("unexpected error reconciling the gateway: %v", err)
+	}
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(work), work); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the work carrying the secret to be removed but got %v, carrying the secret: %v", err, workCarriesSecret(work))
+	}
+}
+
+func workCarriesSecret(work *workv1.ManifestWork) bool {
+	for _, manifest := range work.Spec.Workload.Manifests {
+		if strings.Contains(string(manifest.Raw), `"kind":"Secret"`) {
+			return true
+		}
+	}
+	return false
+}
+
 func TestGatewayReconciler_getTLSSecrets(t *testing.T) {
+	validSecrets := getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace)
+	validDownstreamSecret := validSecrets.Items[0].DeepCopy()
+	validDownstreamSecret.Namespace = testutil.Namespace + "-downstream"
+	if err := addCertificateAnnotations(validDownstreamSecret); err != nil {
+		t.Fatal(err)
+	}
+	// the key pair is valid but the leading PEM block is not the certificate
+	keyFirstSecrets := getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace)
+	keyFirstData := keyFirstSecrets.Items[0].Data
+	keyFirstData[corev1.TLSCertKey] = append(append([]byte{}, keyFirstData[corev1.TLSPrivateKeyKey]...), keyFirstData[corev1.TLSCertKey]...)
 	type fields struct {
 		Client client.Client
 		Scheme *runtime.Scheme
@@ -334,17 +534,19 @@ func TestGatewayReconciler_getTLSSecrets(t *testing.T) {
 		downstreamGateway *gatewayapiv1.Gateway
 	}
 	type testCase struct {
-		name    string
-		fields  fields
-		args    args
-		want    []v1.Object
-		wantErr bool
+		name                 string
+		fields               fields
+		args                 args
+		want                 []v1.Object
+		wantInvalidListeners []gatewayapiv1.SectionName
+		wantEvents           []string
+		wantErr              bool
 	}
 	testCases := []testCase{
 		{
 			name: "returns valid downstream secret for HTTPS listener",
 			fields: fields{
-				Client: testutil.GetValidTestClient(getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace)),
+				Client: testutil.GetValidTestClient(validSecrets),
 				Scheme: testutil.GetValidTestScheme(),
 			},
 			args: args{
@@ -391,11 +593,11 @@ func TestGatewayReconciler_getTLSSecrets(t *testing.T) {
 					},
 				},
 			},
-			want:    []v1.Object{&getValidTLSCertificateSecretList(testutil.TLSSecretName, testutil.Namespace+"-downstream").Items[0]},
+			want:    []v1.Object{validDownstreamSecret},
 			wantErr: false,
 		},
 		{
-			name: "returns error for HTTPS listener when secret doesn't exist",
+			name: "returns invalid listener for HTTPS listener when secret doesn't exist",
 			fields: fields{
 				Client: testutil.GetValidTestClient(),
 				Scheme: testutil.GetValidTestScheme(),
@@ -444,8 +646,172 @@ func TestGatewayReconciler_getTLSSecrets(t *testing.T) {
 					},
 				},
 			},
-			want:    []v1.Object{},
-			wantErr: true,
+			want:                 []v1.Object{},
+			wantInvalidListeners: []gatewayapiv1.SectionName{testutil.ValidTestHostname},
+			wantEvents: []string{
+				fmt.Sprintf("Warning %s secret %s/%s of listener %s not found", TLSSecretMissingReason, testutil.Namespace, testutil.TLSSecretName, testutil.ValidTestHostname),
+			},
+			wantErr: false,
+		},
+		{
+			name: "does not emit the missing secret event again once the listener reports it",
+			fields: fields{
+				Client: testutil.GetValidTestClient(),
+				Scheme: testutil.GetValidTestScheme(),
+			},
+			args: args{
+				upstreamGateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testutil.Namespace,
+						Name:      testutil.DummyCRName,
+					},
+					Status: gatewayapiv1.GatewayStatus{
+						Listeners: []gatewayapiv1.ListenerStatus{
+							{
+								Name: testutil.ValidTestHostname,
+								Conditions: []v1.Condition{
+									buildResolvedRefsCondition(0, fmt.Sprintf("secret %s/%s not found", testutil.Namespace, testutil.TLSSecretName)),
+								},
+							},
+						},
+					},
+					Spec: gatewayapiv1.GatewaySpec{
+						Listeners: []gatewayapiv1.Listener{
+							{
+								Name:     testutil.ValidTestHostname,
+								Hostname: testutil.Pointer(gatewayapiv1.Hostname(testutil.ValidTestHostname)),
+								Port:     0,
+								Protocol: gatewayapiv1.HTTPSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									Mode: testutil.Pointer(gatewayapiv1.TLSModeTerminate),
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Group:     testutil.Pointer(gatewayapiv1.Group("")),
+											Kind:      testutil.Pointer(gatewayapiv1.Kind("secret")),
+											Name:      testutil.TLSSecretName,
+											Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				downstreamGateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testutil.Namespace + "-downstream",
+						Name:      testutil.DummyCRName,
+					},
+					Spec: gatewayapiv1.GatewaySpec{
+						Listeners: []gatewayapiv1.Listener{
+							{
+								Name:     testutil.ValidTestHostname,
+								Hostname: testutil.Pointer(gatewayapiv1.Hostname(testutil.ValidTestHostname)),
+								Protocol: gatewayapiv1.HTTPSProtocolType,
+							},
+						},
+					},
+				},
+			},
+			want:                 []v1.Object{},
+			wantInvalidListeners: []gatewayapiv1.SectionName{testutil.ValidTestHostname},
+			wantErr:              false,
+		},
+		{
+			name: "returns invalid listener when secret is not a valid tls secret",
+			fields: fields{
+				Client: testutil.GetValidTestClient(&corev1.SecretList{
+					Items: []corev1.Secret{
+						{
+							ObjectMeta: v1.ObjectMeta{
+								Name:      testutil.TLSSecretName,
+								Namespace: testutil.Namespace,
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       []byte("foo"),
+								corev1.TLSPrivateKeyKey: []byte("bar"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					},
+				}),
+				Scheme: testutil.GetValidTestScheme(),
+			},
+			args: args{
+				upstreamGateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testutil.Namespace,
+						Name:      testutil.DummyCRName,
+					},
+					Spec: gatewayapiv1.GatewaySpec{
+						Listeners: []gatewayapiv1.Listener{
+							{
+								Name:     testutil.ValidTestHostname,
+								Hostname: testutil.Pointer(gatewayapiv1.Hostname(testutil.ValidTestHostname)),
+								Protocol: gatewayapiv1.HTTPSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									Mode: testutil.Pointer(gatewayapiv1.TLSModeTerminate),
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Name: testutil.TLSSecretName,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				downstreamGateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testutil.Namespace + "-downstream",
+						Name:      testutil.DummyCRName,
+					},
+				},
+			},
+			want:                 []v1.Object{},
+			wantInvalidListeners: []gatewayapiv1.SectionName{testutil.ValidTestHostname},
+			wantErr:              false,
+		},
+		{
+			name: "returns invalid listener when the certificate of the secret can not be parsed",
+			fields: fields{
+				Client: testutil.GetValidTestClient(keyFirstSecrets),
+				Scheme: testutil.GetValidTestScheme(),
+			},
+			args: args{
+				upstreamGateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testutil.Namespace,
+						Name:      testutil.DummyCRName,
+					},
+					Spec: gatewayapiv1.GatewaySpec{
+						Listeners: []gatewayapiv1.Listener{
+							{
+								Name:     testutil.ValidTestHostname,
+								Hostname: testutil.Pointer(gatewayapiv1.Hostname(testutil.ValidTestHostname)),
+								Protocol: gatewayapiv1.HTTPSProtocolType,
+								TLS: &gatewayapiv1.GatewayTLSConfig{
+									Mode: testutil.Pointer(gatewayapiv1.TLSModeTerminate),
+									CertificateRefs: []gatewayapiv1.SecretObjectReference{
+										{
+											Name: testutil.TLSSecretName,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				downstreamGateway: &gatewayapiv1.Gateway{
+					ObjectMeta: v1.ObjectMeta{
+						Namespace: testutil.Namespace + "-downstream",
+						Name:      testutil.DummyCRName,
+					},
+				},
+			},
+			want:                 []v1.Object{},
+			wantInvalidListeners: []gatewayapiv1.SectionName{testutil.ValidTestHostname},
+			wantErr:              false,
 		},
 		{
 			name: "returns empty list for HTTP listener",
@@ -473,12 +839,14 @@ func TestGatewayReconciler_getTLSSecrets(t *testing.T) {
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
 			r := &GatewayReconciler{
 				Client:    testCase.fields.Client,
 				Scheme:    testCase.fields.Scheme,
 				Placement: fakeplacement.NewTestGatewayPlacer(),
+				Recorder:  recorder,
 			}
-			got, err := r.getTLSSecrets(context.TODO(), testCase.args.upstreamGateway, testCase.args.downstreamGateway)
+			got, invalidListeners, err := r.getTLSSecrets(context.TODO(), testCase.args.upstreamGateway, testCase.args.downstreamGateway, nil)
 			if (err != nil) != testCase.wantErr {
 				t.Errorf("reconcileTLS() error = %v, wantErr %v", err, testCase.wantErr)
 				return
@@ -486,6 +854,266 @@ func TestGatewayReconciler_getTLSSecrets(t *testing.T) {
 			if !verifyTLSSecretTestResultsAsExpected(got, testCase.want, testCase.args.downstreamGateway) {
 				t.Errorf("reconcileTLS() \ngot: \n%v \nwant: \n%v", got, testCase.want)
 			}
+			if len(invalidListeners) != len(testCase.wantInvalidListeners) {
+				t.Errorf("reconcileTLS() invalid listeners = %v, want %v", invalidListeners, testCase.wantInvalidListeners)
+			}
+			for _, name := range testCase.wantInvalidListeners {
+				condition, ok := invalidListeners[name]
+				if !ok || condition.Reason != string(gatewayapiv1.ListenerReasonInvalidCertificateRef) {
+					t.Errorf("reconcileTLS() expected listener %s to have an InvalidCertificateRef condition, got %v", name, condition)
+				}
+			}
+			close(recorder.Events)
+			events := []string{}
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			if len(events) != len(testCase.wantEvents) || (len(events) > 0 && !reflect.DeepEqual(events, testCase.wantEvents)) {
+				t.Errorf("reconcileTLS() events = %v, want %v", events, testCase.wantEvents)
+			}
+		})
+	}
+}
+
+func TestManagedGatewayPredicate(t *testing.T) {
+	c := testutil.GetValidTestClient(&gatewayapiv1.GatewayClassList{
+		Items: []gatewayapiv1.GatewayClass{
+			{
+				ObjectMeta: v1.ObjectMeta{Name: "managed"},
+				Spec:       gatewayapiv1.GatewayClassSpec{ControllerName: ControllerName},
+			},
+			{
+				ObjectMeta: v1.ObjectMeta{Name: "other"},
+				Spec:       gatewayapiv1.GatewayClassSpec{ControllerName: "example.com/some-other-controller"},
+			},
+		},
+	})
+	now := v1.Now()
+	testCases := []struct {
+		name    string
+		gateway *gatewayapiv1.Gateway
+		want    bool
+	}{
+		{
+			name:    "gateway of a managed class",
+			gateway: &gatewayapiv1.Gateway{Spec: gatewayapiv1.GatewaySpec{GatewayClassName: "managed"}},
+			want:    true,
+		},
+		{
+			name:    "gateway of another controller",
+			gateway: &gatewayapiv1.Gateway{Spec: gatewayapiv1.GatewaySpec{GatewayClassName: "other"}},
+			want:    false,
+		},
+		{
+			name:    "gateway of a missing class",
+			gateway: &gatewayapiv1.Gateway{Spec: gatewayapiv1.GatewaySpec{GatewayClassName: "missing"}},
+			want:    false,
+		},
+		{
+			name: "placed gateway of a deleted class",
+			gateway: &gatewayapiv1.Gateway{
+				ObjectMeta: v1.ObjectMeta{Finalizers: []string{GatewayFinalizer}},
+				Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: "missing"},
+			},
+			want: true,
+		},
+		{
+			name: "deleting gateway of another controller",
+			gateway: &gatewayapiv1.Gateway{
+				ObjectMeta: v1.ObjectMeta{DeletionTimestamp: &now, Finalizers: []string{"example.com/finalizer"}},
+				Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: "other"},
+			},
+			want: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := managedGatewayPredicate(context.TODO(), c).Generic(event.GenericEvent{Object: testCase.gateway}); got != testCase.want {
+				t.Errorf("expected %v but got %v", testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestPolicyDownstream(t *testing.T) {
+	c := testutil.GetValidTestClient(
+		&gatewayapiv1.GatewayClassList{
+			Items: []gatewayapiv1.GatewayClass{
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "managed"},
+					Spec:       gatewayapiv1.GatewayClassSpec{ControllerName: ControllerName},
+				},
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "other"},
+					Spec:       gatewayapiv1.GatewayClassSpec{ControllerName: "example.com/some-other-controller"},
+				},
+			},
+		},
+		&gatewayapiv1.GatewayList{
+			Items: []gatewayapiv1.Gateway{
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "placed", Namespace: testutil.Namespace},
+					Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: "managed"},
+				},
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "unmanaged", Namespace: testutil.Namespace},
+					Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: "other"},
+				},
+			},
+		},
+	)
+	r := &GatewayReconciler{Client: c, Scheme: testutil.GetValidTestScheme()}
+	policy := func(namespace, kind, name string) policysync.Policy {
+		policy, err := policysync.NewPolicyFor(&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "policy", "namespace": namespace},
+				"spec": map[string]interface{}{
+					"targetRef": map[string]interface{}{"group": gatewayapiv1.GroupName, "kind": kind, "name": name},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return policy
+	}
+	testCases := []struct {
+		name           string
+		policy         policysync.Policy
+		wantDownstream *types.NamespacedName
+	}{
+		{
+			name:           "policy targeting a placed gateway",
+			policy:         policy(testutil.Namespace, "Gateway", "placed"),
+			wantDownstream: &types.NamespacedName{Namespace: "kuadrant-" + testutil.Namespace, Name: "placed"},
+		},
+		{
+			name:   "policy targeting a gateway of another controller",
+			policy: policy(testutil.Namespace, "Gateway", "unmanaged"),
+		},
+		{
+			name:   "policy targeting a gateway of the same name in another namespace",
+			policy: policy("other", "Gateway", "placed"),
+		},
+		{
+			name:   "policy targeting a route",
+			policy: policy(testutil.Namespace, "HTTPRoute", "placed"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			upstream, downstream, err := r.policyDownstream(context.TODO(), testCase.policy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if testCase.wantDownstream == nil {
+				if upstream != nil || downstream != nil {
+					t.Errorf("expected no gateway but got %v and %v", upstream, downstream)
+				}
+				return
+			}
+			if upstream == nil || downstream == nil {
+				t.Fatalf("expected the gateway to be resolved")
+			}
+			if key := client.ObjectKeyFromObject(downstream); key != *testCase.wantDownstream {
+				t.Errorf("expected downstream gateway %v but got %v", *testCase.wantDownstream, key)
+			}
+		})
+	}
+}
+
+func TestPolicyMetadata(t *testing.T) {
+	c := testutil.GetValidTestClient(
+		&gatewayapiv1.GatewayClassList{
+			Items: []gatewayapiv1.GatewayClass{
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "team-a"},
+					Spec: gatewayapiv1.GatewayClassSpec{
+						ControllerName: ControllerName,
+						ParametersRef: &gatewayapiv1.ParametersReference{
+							Group:     gatewayapiv1.Group(v1alpha1.GroupVersion.Group),
+							Kind:      "GatewayClassParameters",
+							Name:      "team-a",
+							Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+						},
+					},
+				},
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "default"},
+					Spec:       gatewayapiv1.GatewayClassSpec{ControllerName: ControllerName},
+				},
+			},
+		},
+		&v1alpha1.GatewayClassParametersList{
+			Items: []v1alpha1.GatewayClassParameters{
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "team-a", Namespace: testutil.Namespace},
+					Spec: v1alpha1.GatewayClassParametersSpec{
+						DownstreamMetadata: &v1alpha1.DownstreamMetadata{
+							Labels: v1alpha1.MetadataRules{Inject: map[string]string{"team": "a"}},
+						},
+					},
+				},
+			},
+		},
+		&gatewayapiv1.GatewayList{
+			Items: []gatewayapiv1.Gateway{
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "team-a", Namespace: testutil.Namespace},
+					Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: "team-a"},
+				},
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "default", Namespace: testutil.Namespace},
+					Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: "default"},
+				},
+			},
+		},
+	)
+	r := &GatewayReconciler{Client: c, Scheme: testutil.GetValidTestScheme()}
+	testCases := []struct {
+		name       string
+		gateway    string
+		wantLabels map[string]string
+	}{
+		{
+			name:       "policy targeting a gateway of a class injecting labels",
+			gateway:    "team-a",
+			wantLabels: map[string]string{"app": "test", "team": "a"},
+		},
+		{
+			name:       "policy targeting a gateway of a class without params",
+			gateway:    "default",
+			wantLabels: map[string]string{"app": "test"},
+		},
+		{
+			name:       "policy targeting a missing gateway",
+			gateway:    "missing",
+			wantLabels: map[string]string{"app": "test"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			policy, err := policysync.NewPolicyFor(&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name":      "policy",
+						"namespace": testutil.Namespace,
+						"labels":    map[string]interface{}{"app": "test"},
+					},
+					"spec": map[string]interface{}{
+						"targetRef": map[string]interface{}{"group": gatewayapiv1.GroupName, "kind": "Gateway", "name": testCase.gateway},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := r.policyMetadata(context.TODO(), policy); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(policy.GetLabels(), testCase.wantLabels) {
+				t.Errorf("expected labels %v but got %v", testCase.wantLabels, policy.GetLabels())
+			}
 		})
 	}
 }
@@ -609,6 +1237,7 @@ func verifyTLSSecretTestResultsAsExpected(got []v1.Object, want []v1.Object, gat
 }
 
 func getValidTLSCertificateSecretList(name, namespace string) *corev1.SecretList {
+	cert, key := testutil.GenerateTLSCertificate(testutil.ValidTestHostname, time.Now().Add(24*time.Hour))
 	return &corev1.SecretList{
 		Items: []corev1.Secret{
 			{
@@ -620,9 +1249,10 @@ func getValidTLSCertificateSecretList(name, namespace string) *corev1.SecretList
 					},
 				},
 				Data: map[string][]byte{
-					"ca.crt": []byte("foo"),
+					corev1.TLSCertKey:       cert,
+					corev1.TLSPrivateKeyKey: key,
 				},
-				Type: "type: kubernetes.io/tls",
+				Type: corev1.SecretTypeTLS,
 			},
 		},
 	}
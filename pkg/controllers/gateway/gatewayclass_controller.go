@@ -19,26 +19,51 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
 )
 
 const (
 	ControllerName = "kuadrant.io/mgc-gw-controller"
+
+	gatewayAPIBundleVersionAnnotation = "gateway.networking.k8s.io/bundle-version"
+	// supportedGatewayAPIVersion is the Gateway API minor version the controller is built against
+	supportedGatewayAPIVersion = "v1.0"
 )
 
-func getSupportedClasses() []string {
-	return []string{"kuadrant-multi-cluster-gateway-instance-per-cluster"}
+// gatewayAPICRDs are the Gateway API CRDs used by the controller
+var gatewayAPICRDs = []string{
+	"gatewayclasses.gateway.networking.k8s.io",
+	"gateways.gateway.networking.k8s.io",
+	"referencegrants.gateway.networking.k8s.io",
+}
+
+// isManagedGatewayClass checks whether the GatewayClass of the given name is handled by this controller
+func isManagedGatewayClass(ctx context.Context, c client.Reader, name string) (bool, error) {
+	gatewayClass := &gatewayapiv1.GatewayClass{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, gatewayClass); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return gatewayClass.Spec.ControllerName == ControllerName, nil
 }
 
 // GatewayClassReconciler reconciles a GatewayClass object
@@ -51,6 +76,7 @@ type GatewayClassReconciler struct {
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
 func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := ctrllog.FromContext(ctx)
@@ -65,55 +91,36 @@ func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
-	if gatewayClassIsAccepted(previous) {
-		log.V(3).Info("GatewayClass already Accepted", "class", previous.Name)
-		return ctrl.Result{}, nil
-	}
-
 	gatewayclass := previous.DeepCopy()
-	supportedClasses := getSupportedClasses()
 
 	_, err = getParams(ctx, r.Client, previous.Name)
+	if err != nil && !IsInvalidParamsError(err) {
+		return ctrl.Result{}, err
+	}
 
-	if !slice.ContainsString(supportedClasses, previous.Name) {
-		gatewayclass.Status = gatewayapiv1.GatewayClassStatus{
-			Conditions: []metav1.Condition{
-				{
-					LastTransitionTime: metav1.Now(),
-					Message:            fmt.Sprintf("Invalid Parameters - Unsupported class name %s. Must be one of [%v]", previous.Name, strings.Join(supportedClasses, ",")),
-					Reason:             string(gatewayapiv1.GatewayClassReasonInvalidParameters),
-					Status:             metav1.ConditionFalse,
-					Type:               string(gatewayapiv1.GatewayClassConditionStatusAccepted),
-					ObservedGeneration: previous.Generation,
-				},
-			},
-		}
-	} else if IsInvalidParamsError(err) {
-		gatewayclass.Status = gatewayapiv1.GatewayClassStatus{
-			Conditions: []metav1.Condition{
-				{
-					LastTransitionTime: metav1.Now(),
-					Message:            fmt.Sprintf("Invalid Parameters - %s", err.Error()),
-					Reason:             string(gatewayapiv1.GatewayClassReasonInvalidParameters),
-					Status:             metav1.ConditionFalse,
-					Type:               string(gatewayapiv1.GatewayClassConditionStatusAccepted),
-					ObservedGeneration: previous.Generation,
-				},
-			},
-		}
-	} else {
-		gatewayclass.Status = gatewayapiv1.GatewayClassStatus{
-			Conditions: []metav1.Condition{
-				{
-					LastTransitionTime: metav1.Now(),
-					Message:            fmt.Sprintf("Handled by %s", ControllerName),
-					Reason:             string(gatewayapiv1.GatewayClassConditionStatusAccepted),
-					Status:             metav1.ConditionTrue,
-					Type:               string(gatewayapiv1.GatewayClassConditionStatusAccepted),
-					ObservedGeneration: previous.Generation,
-				},
-			},
-		}
+	// acceptance is re-evaluated on every change as the parameters may have become invalid since the class was accepted
+	acceptedCondition := metav1.Condition{
+		Type:               string(gatewayapiv1.GatewayClassConditionStatusAccepted),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gatewayapiv1.GatewayClassReasonAccepted),
+		Message:            fmt.Sprintf("Handled by %s", ControllerName),
+		ObservedGeneration: previous.Generation,
+	}
+	if IsInvalidParamsError(err) {
+		acceptedCondition.Status = metav1.ConditionFalse
+		acceptedCondition.Reason = string(gatewayapiv1.GatewayClassReasonInvalidParameters)
+		acceptedCondition.Message = fmt.Sprintf("Invalid Parameters - %s", err.Error())
+	}
+	meta.SetStatusCondition(&gatewayclass.Status.Conditions, acceptedCondition)
+
+	supportedVersionCondition, err := buildSupportedVersionCondition(ctx, r.Client, previous.Generation)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	meta.SetStatusCondition(&gatewayclass.Status.Conditions, supportedVersionCondition)
+
+	if reflect.DeepEqual(previous.Status, gatewayclass.Status) {
+		return ctrl.Result{}, nil
 	}
 
 	log.Info("Updating GatewayClass", "status", gatewayclass.Status)
@@ -125,19 +132,112 @@ func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	return ctrl.Result{}, nil
 }
 
-func gatewayClassIsAccepted(gatewayClass *gatewayapiv1.GatewayClass) bool {
-	acceptedCondition := meta.FindStatusCondition(gatewayClass.Status.Conditions, string(gatewayapiv1.GatewayConditionAccepted))
-	return (acceptedCondition != nil && acceptedCondition.Status == metav1.ConditionTrue)
+// buildSupportedVersionCondition reports whether the bundle version of the installed Gateway API CRDs used by the
+// controller is supported. CRDs that are missing or have no bundle version are reported as unsupported
+func buildSupportedVersionCondition(ctx context.Context, c client.Client, generation int64) (metav1.Condition, error) {
+	condition := metav1.Condition{
+		Type:               string(gatewayapiv1.GatewayClassConditionStatusSupportedVersion),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gatewayapiv1.GatewayClassReasonSupportedVersion),
+		ObservedGeneration: generation,
+	}
+	versions := []string{}
+	unsupported := []string{}
+	for _, name := range gatewayAPICRDs {
+		// only the metadata of the CRDs is read, their schemas are not cached
+		crd := &metav1.PartialObjectMetadata{}
+		crd.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+		if err := c.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return condition, err
+			}
+			unsupported = append(unsupported, fmt.Sprintf("%s not installed", name))
+			continue
+		}
+		version, ok := crd.Annotations[gatewayAPIBundleVersionAnnotation]
+		if !ok {
+			unsupported = append(unsupported, fmt.Sprintf("%s has no bundle version", name))
+			continue
+		}
+		if !isSupportedGatewayAPIVersion(version) {
+			unsupported = append(unsupported, fmt.Sprintf("%s has bundle version %s", name, version))
+			continue
+		}
+		if !slice.ContainsString(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+	if len(unsupported) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(gatewayapiv1.GatewayClassReasonUnsupportedVersion)
+		condition.Message = fmt.Sprintf("Unsupported Gateway API version: %s. Supported versions are %s.x", strings.Join(unsupported, ", "), supportedGatewayAPIVersion)
+		return condition, nil
+	}
+	condition.Message = fmt.Sprintf("Gateway API version %s is supported", strings.Join(versions, ", "))
+	return condition, nil
+}
+
+// isSupportedGatewayAPIVersion checks the bundle version is a patch release of the supported Gateway API version
+func isSupportedGatewayAPIVersion(version string) bool {
+	return version == supportedGatewayAPIVersion || strings.HasPrefix(version, supportedGatewayAPIVersion+".")
+}
+
+// paramsToGatewayClasses maps an object to the GatewayClasses of this controller that reference it as parameters
+func (r *GatewayClassReconciler) paramsToGatewayClasses(ctx context.Context, o client.Object) []reconcile.Request {
+	log := ctrllog.FromContext(ctx)
+	gvk, err := apiutil.GVKForObject(o, r.Scheme)
+	if err != nil {
+		log.Error(err, "failed to get kind of gateway class parameters", "parameters", client.ObjectKeyFromObject(o))
+		return nil
+	}
+	gatewayClasses := &gatewayapiv1.GatewayClassList{}
+	// the GatewayClassParamsIndex is registered by the GatewayReconciler
+	if err := r.Client.List(ctx, gatewayClasses, client.MatchingFields{GatewayClassParamsIndex: paramsKey(gvk.GroupKind(), o.GetNamespace(), o.GetName())}); err != nil {
+		log.Error(err, "failed to list gateway classes for parameters", "parameters", client.ObjectKeyFromObject(o))
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, gatewayClass := range gatewayClasses.Items {
+		if gatewayClass.Spec.ControllerName != ControllerName {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gatewayClass)})
+	}
+	return requests
+}
+
+// crdToGatewayClasses maps a Gateway API CRD used by the controller to the GatewayClasses of this controller so that
+// the supported version is reported again when the CRDs are installed or upgraded
+func (r *GatewayClassReconciler) crdToGatewayClasses(ctx context.Context, o client.Object) []reconcile.Request {
+	if !slice.ContainsString(gatewayAPICRDs, o.GetName()) {
+		return nil
+	}
+	log := ctrllog.FromContext(ctx)
+	gatewayClasses := &gatewayapiv1.GatewayClassList{}
+	if err := r.Client.List(ctx, gatewayClasses); err != nil {
+		log.Error(err, "failed to list gateway classes for CRD", "crd", o.GetName())
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, gatewayClass := range gatewayClasses.Items {
+		if gatewayClass.Spec.ControllerName != ControllerName {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gatewayClass)})
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GatewayClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		// Uncomment the following line adding a pointer to an instance of the controlled resource as an argument
-		For(&gatewayapiv1.GatewayClass{}).
-		WithEventFilter(predicate.NewPredicateFuncs(func(object client.Object) bool {
+		For(&gatewayapiv1.GatewayClass{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
 			gatewayClass := object.(*gatewayapiv1.GatewayClass)
 			return gatewayClass.Spec.ControllerName == ControllerName
-		})).
+		}))).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.paramsToGatewayClasses)).
+		Watches(&v1alpha1.GatewayClassParameters{}, handler.EnqueueRequestsFromMapFunc(r.paramsToGatewayClasses)).
+		// only the metadata of the CRDs is needed, their schemas are not cached
+		Watches(&apiextensionsv1.CustomResourceDefinition{}, handler.EnqueueRequestsFromMapFunc(r.crdToGatewayClasses), builder.OnlyMetadata).
 		Complete(r)
 }
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -37,7 +39,7 @@ func TestGatewayClassReconciler_Reconcile(t *testing.T) {
 						Items: []gatewayapiv1.GatewayClass{
 							{
 								ObjectMeta: v1.ObjectMeta{
-									Name: testutil.DummyCRName,
+									Name: testutil.MultiClusterGatewayClassName,
 								},
 								Status: gatewayapiv1.GatewayClassStatus{
 									Conditions: []v1.Condition{
@@ -53,9 +55,81 @@ func TestGatewayClassReconciler_Reconcile(t *testing.T) {
 				),
 			},
 			args: args{
-				req: buildGCTestRequest(),
+				req: ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name: testutil.MultiClusterGatewayClassName,
+					},
+				},
+			},
+			verify: verifyGatewayClassAcceptance(testutil.MultiClusterGatewayClassName, true),
+		},
+		{
+			name: "Accepted gateway class with parameters that became invalid",
+			fields: fields{
+				Client: testutil.GetValidTestClient(
+					&gatewayapiv1.GatewayClassList{
+						Items: []gatewayapiv1.GatewayClass{
+							{
+								ObjectMeta: v1.ObjectMeta{
+									Name:       testutil.MultiClusterGatewayClassName,
+									Generation: 2,
+								},
+								Spec: gatewayapiv1.GatewayClassSpec{
+									ParametersRef: &gatewayapiv1.ParametersReference{
+										Group:     "",
+										Kind:      "ConfigMap",
+										Name:      "test-params",
+										Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+									},
+								},
+								Status: gatewayapiv1.GatewayClassStatus{
+									Conditions: []v1.Condition{
+										{
+											Type:               string(gatewayapiv1.GatewayConditionAccepted),
+											Status:             v1.ConditionTrue,
+											ObservedGeneration: 1,
+										},
+									},
+								},
+							},
+						},
+					},
+					&corev1.ConfigMapList{
+						Items: []corev1.ConfigMap{
+							{
+								ObjectMeta: v1.ObjectMeta{
+									Name:      "test-params",
+									Namespace: testutil.Namespace,
+								},
+								Data: map[string]string{
+									"params": `{"downstreamClass": "istio" boop`,
+								},
+							},
+						},
+					},
+				),
+			},
+			args: args{
+				req: ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Name: testutil.MultiClusterGatewayClassName,
+					},
+				},
+			},
+			verify: func(c client.Client, res ctrl.Result, err error, t *testing.T) {
+				verifyGatewayClassAcceptance(testutil.MultiClusterGatewayClassName, false)(c, res, err, t)
+				class := &gatewayapiv1.GatewayClass{}
+				if err := c.Get(context.TODO(), client.ObjectKey{Name: testutil.MultiClusterGatewayClassName}, class); err != nil {
+					t.Fatalf("error getting gateway class from client: %s", err)
+				}
+				accepted := meta.FindStatusCondition(class.Status.Conditions, string(gatewayapiv1.GatewayClassConditionStatusAccepted))
+				if accepted.Reason != string(gatewayapiv1.GatewayClassReasonInvalidParameters) {
+					t.Errorf("expected reason %s but got %s", gatewayapiv1.GatewayClassReasonInvalidParameters, accepted.Reason)
+				}
+				if accepted.ObservedGeneration != 2 {
+					t.Errorf("expected observed generation 2 but got %d", accepted.ObservedGeneration)
+				}
 			},
-			verify: verifyGatewayClassAcceptance(testutil.DummyCRName, true),
 		},
 		{
 			name: "Gateway class being accepted",
@@ -65,7 +139,7 @@ func TestGatewayClassReconciler_Reconcile(t *testing.T) {
 						Items: []gatewayapiv1.GatewayClass{
 							{
 								ObjectMeta: v1.ObjectMeta{
-									Name: getSupportedClasses()[0],
+									Name: testutil.MultiClusterGatewayClassName,
 								},
 								Spec: gatewayapiv1.GatewayClassSpec{
 									ParametersRef: &gatewayapiv1.ParametersReference{
@@ -96,14 +170,14 @@ func TestGatewayClassReconciler_Reconcile(t *testing.T) {
 			args: args{
 				req: ctrl.Request{
 					NamespacedName: types.NamespacedName{
-						Name: getSupportedClasses()[0],
+						Name: testutil.MultiClusterGatewayClassName,
 					},
 				},
 			},
-			verify: verifyGatewayClassAcceptance(getSupportedClasses()[0], true),
+			verify: verifyGatewayClassAcceptance(testutil.MultiClusterGatewayClassName, true),
 		},
 		{
-			name: "Unsupported class name",
+			name: "Gateway class with any name",
 			fields: fields{
 				Client: testutil.GetValidTestClient(
 					&gatewayapiv1.GatewayClassList{
@@ -120,7 +194,7 @@ func TestGatewayClassReconciler_Reconcile(t *testing.T) {
 			args: args{
 				req: buildGCTestRequest(),
 			},
-			verify: verifyGatewayClassAcceptance(testutil.DummyCRName, false),
+			verify: verifyGatewayClassAcceptance(testutil.DummyCRName, true),
 		},
 		{
 			name: "Invalid Parameters in config map",
@@ -130,7 +204,7 @@ func TestGatewayClassReconciler_Reconcile(t *testing.T) {
 						Items: []gatewayapiv1.GatewayClass{
 							{
 								ObjectMeta: v1.ObjectMeta{
-									Name: getSupportedClasses()[0],
+									Name: testutil.MultiClusterGatewayClassName,
 								},
 								Spec: gatewayapiv1.GatewayClassSpec{
 									ParametersRef: &gatewayapiv1.ParametersReference{
@@ -161,11 +235,11 @@ func TestGatewayClassReconciler_Reconcile(t *testing.T) {
 			args: args{
 				req: ctrl.Request{
 					NamespacedName: types.NamespacedName{
-						Name: getSupportedClasses()[0],
+						Name: testutil.MultiClusterGatewayClassName,
 					},
 				},
 			},
-			verify: verifyGatewayClassAcceptance(getSupportedClasses()[0], false),
+			verify: verifyGatewayClassAcceptance(testutil.MultiClusterGatewayClassName, false),
 		},
 		{
 			name: "Gateway class not found",
@@ -175,7 +249,7 @@ func TestGatewayClassReconciler_Reconcile(t *testing.T) {
 						Items: []gatewayapiv1.GatewayClass{
 							{
 								ObjectMeta: v1.ObjectMeta{
-									Name: getSupportedClasses()[0],
+									Name: testutil.MultiClusterGatewayClassName,
 								},
 							},
 						},
@@ -222,8 +296,99 @@ func verifyGatewayClassAcceptance(name string, want bool) func(c client.Client,
 		if err != nil {
 			t.Fatalf("error getting gateway class from client: %s", err)
 		}
-		if want != gatewayClassIsAccepted(class) {
+		if want != meta.IsStatusConditionTrue(class.Status.Conditions, string(gatewayapiv1.GatewayClassConditionStatusAccepted)) {
 			t.Fatalf("controller ignored or not accepted gateway class")
 		}
 	}
 }
+
+func TestBuildSupportedVersionCondition(t *testing.T) {
+	crds := func(version string) *apiextensionsv1.CustomResourceDefinitionList {
+		list := &apiextensionsv1.CustomResourceDefinitionList{}
+		for _, name := range gatewayAPICRDs {
+			crd := apiextensionsv1.CustomResourceDefinition{ObjectMeta: v1.ObjectMeta{Name: name}}
+			if version != "" {
+				crd.Annotations = map[string]string{gatewayAPIBundleVersionAnnotation: version}
+			}
+			list.Items = append(list.Items, crd)
+		}
+		return list
+	}
+	testCases := []struct {
+		name       string
+		client     client.Client
+		wantStatus v1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "supported version",
+			client:     testutil.GetValidTestClient(crds("v1.0.0")),
+			wantStatus: v1.ConditionTrue,
+			wantReason: string(gatewayapiv1.GatewayClassReasonSupportedVersion),
+		},
+		{
+			name:       "unsupported version",
+			client:     testutil.GetValidTestClient(crds("v0.8.1")),
+			wantStatus: v1.ConditionFalse,
+			wantReason: string(gatewayapiv1.GatewayClassReasonUnsupportedVersion),
+		},
+		{
+			name:       "missing bundle version",
+			client:     testutil.GetValidTestClient(crds("")),
+			wantStatus: v1.ConditionFalse,
+			wantReason: string(gatewayapiv1.GatewayClassReasonUnsupportedVersion),
+		},
+		{
+			name:       "CRDs not installed",
+			client:     testutil.GetValidTestClient(),
+			wantStatus: v1.ConditionFalse,
+			wantReason: string(gatewayapiv1.GatewayClassReasonUnsupportedVersion),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			condition, err := buildSupportedVersionCondition(context.TODO(), testCase.client, 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if condition.Status != testCase.wantStatus || condition.Reason != testCase.wantReason {
+				t.Errorf("expected %s %s but got %s %s: %s", testCase.wantStatus, testCase.wantReason, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
+
+func TestIsManagedGatewayClass(t *testing.T) {
+	c := testutil.GetValidTestClient(&gatewayapiv1.GatewayClassList{
+		Items: []gatewayapiv1.GatewayClass{
+			{
+				ObjectMeta: v1.ObjectMeta{Name: "managed"},
+				Spec:       gatewayapiv1.GatewayClassSpec{ControllerName: ControllerName},
+			},
+			{
+				ObjectMeta: v1.ObjectMeta{Name: "other"},
+				Spec:       gatewayapiv1.GatewayClassSpec{ControllerName: "example.com/some-other-controller"},
+			},
+		},
+	})
+	testCases := []struct {
+		name      string
+		className string
+		want      bool
+	}{
+		{name: "class of this controller", className: "managed", want: true},
+		{name: "class of another controller", className: "other", want: false},
+		{name: "class not found", className: "missing", want: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := isManagedGatewayClass(context.TODO(), c, testCase.className)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != testCase.want {
+				t.Errorf("expected %v but got %v", testCase.want, got)
+			}
+		})
+	}
+}
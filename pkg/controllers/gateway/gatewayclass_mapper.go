@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// GatewayClassNameIndex indexes gateways by the name of their GatewayClass
+	GatewayClassNameIndex = "spec.gatewayClassName"
+	// GatewayClassParamsIndex indexes GatewayClasses by the object referenced by their parametersRef
+	GatewayClassParamsIndex = "spec.parametersRef"
+)
+
+// gatewayClassName is the GatewayClassNameIndex extractor
+func gatewayClassName(o client.Object) []string {
+	gateway, ok := o.(*gatewayapiv1.Gateway)
+	if !ok {
+		return nil
+	}
+	return []string{string(gateway.Spec.GatewayClassName)}
+}
+
+// gatewayClassParams is the GatewayClassParamsIndex extractor
+func gatewayClassParams(o client.Object) []string {
+	gatewayClass, ok := o.(*gatewayapiv1.GatewayClass)
+	if !ok || gatewayClass.Spec.ParametersRef == nil {
+		return nil
+	}
+	ref := gatewayClass.Spec.ParametersRef
+	namespace := ""
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	return []string{paramsKey(schema.GroupKind{Group: string(ref.Group), Kind: string(ref.Kind)}, namespace, ref.Name)}
+}
+
+func paramsKey(groupKind schema.GroupKind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", groupKind.String(), namespace, name)
+}
+
+// gatewayClassToGateways maps a GatewayClass to its gateways so that changes to the class are applied downstream
+func (r *GatewayReconciler) gatewayClassToGateways(ctx context.Context, o client.Object) []reconcile.Request {
+	if gatewayClass, ok := o.(*gatewayapiv1.GatewayClass); !ok || gatewayClass.Spec.ControllerName != ControllerName {
+		return nil
+	}
+	return r.enqueueGatewaysOfClasses(ctx, o.GetName())
+}
+
+// paramsToGateways maps an object referenced by the parametersRef of GatewayClasses to the gateways of those classes
+func (r *GatewayReconciler) paramsToGateways(ctx context.Context, o client.Object) []reconcile.Request {
+	log := crlog.FromContext(ctx)
+	gvk, err := apiutil.GVKForObject(o, r.Scheme)
+	if err != nil {
+		log.Error(err, "failed to get kind of gateway class parameters", "parameters", client.ObjectKeyFromObject(o))
+		return nil
+	}
+	gatewayClasses := &gatewayapiv1.GatewayClassList{}
+	if err := r.Client.List(ctx, gatewayClasses, client.MatchingFields{GatewayClassParamsIndex: paramsKey(gvk.GroupKind(), o.GetNamespace(), o.GetName())}); err != nil {
+		log.Error(err, "failed to list gateway classes for parameters", "parameters", client.ObjectKeyFromObject(o))
+		return nil
+	}
+	classNames := make([]string, 0, len(gatewayClasses.Items))
+	for _, gatewayClass := range gatewayClasses.Items {
+		if gatewayClass.Spec.ControllerName != ControllerName {
+			continue
+		}
+		classNames = append(classNames, gatewayClass.Name)
+	}
+	return r.enqueueGatewaysOfClasses(ctx, classNames...)
+}
+
+func (r *GatewayReconciler) enqueueGatewaysOfClasses(ctx context.Context, classNames ...string) []reconcile.Request {
+	log := crlog.FromContext(ctx)
+	requests := []reconcile.Request{}
+	for _, className := range classNames {
+		gateways := &gatewayapiv1.GatewayList{}
+		if err := r.Client.List(ctx, gateways, client.MatchingFields{GatewayClassNameIndex: className}); err != nil {
+			log.Error(err, "failed to list gateways for gateway class", "gatewayclass", className)
+			continue
+		}
+		for _, gateway := range gateways.Items {
+			log.V(3).Info("enqueuing gateway based on gateway class change", "gateway", gateway.Name, "namespace", gateway.Namespace, "gatewayclass", className)
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,143 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestGatewayClassMapping(t *testing.T) {
+	gateway := func(name, className string) gatewayapiv1.Gateway {
+		return gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testutil.Namespace},
+			Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: gatewayapiv1.ObjectName(className)},
+		}
+	}
+	gatewayClass := func(name, paramsName string) gatewayapiv1.GatewayClass {
+		return gatewayapiv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: gatewayapiv1.GatewayClassSpec{
+				ControllerName: ControllerName,
+				ParametersRef: &gatewayapiv1.ParametersReference{
+					Group:     "",
+					Kind:      "ConfigMap",
+					Name:      paramsName,
+					Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+				},
+			},
+		}
+	}
+	r := &GatewayReconciler{
+		Scheme: testutil.GetValidTestScheme(),
+		Client: fake.NewClientBuilder().
+			WithScheme(testutil.GetValidTestScheme()).
+			WithLists(
+				&gatewayapiv1.GatewayList{
+					Items: []gatewayapiv1.Gateway{
+						gateway("a-1", "class-a"),
+						gateway("a-2", "class-a"),
+						gateway("b-1", "class-b"),
+					},
+				},
+				&gatewayapiv1.GatewayClassList{
+					Items: []gatewayapiv1.GatewayClass{
+						gatewayClass("class-a", "params-a"),
+						gatewayClass("class-b", "params-b"),
+					},
+				},
+			).
+			WithIndex(&gatewayapiv1.Gateway{}, GatewayClassNameIndex, gatewayClassName).
+			WithIndex(&gatewayapiv1.GatewayClass{}, GatewayClassParamsIndex, gatewayClassParams).
+			Build(),
+	}
+	requestNames := func(requests []reconcile.Request) []string {
+		names := []string{}
+		for _, request := range requests {
+			names = append(names, request.Name)
+		}
+		return names
+	}
+
+	classA := gatewayClass("class-a", "params-a")
+	if names := requestNames(r.gatewayClassToGateways(context.TODO(), &classA)); len(names) != 2 || names[0] != "a-1" || names[1] != "a-2" {
+		t.Errorf("expected the gateways of class-a to be enqueued but got %v", names)
+	}
+
+	params := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "params-b", Namespace: testutil.Namespace}}
+	if names := requestNames(r.paramsToGateways(context.TODO(), params)); len(names) != 1 || names[0] != "b-1" {
+		t.Errorf("expected the gateways of class-b to be enqueued but got %v", names)
+	}
+
+	otherController := gatewayClass("class-a", "params-a")
+	otherController.Spec.ControllerName = "example.com/some-other-controller"
+	if names := requestNames(r.gatewayClassToGateways(context.TODO(), &otherController)); len(names) != 0 {
+		t.Errorf("expected no gateways to be enqueued for a class of another controller but got %v", names)
+	}
+
+	unreferenced := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "params-b", Namespace: "other"}}
+	if names := requestNames(r.paramsToGateways(context.TODO(), unreferenced)); len(names) != 0 {
+		t.Errorf("expected no gateways to be enqueued for an unreferenced ConfigMap but got %v", names)
+	}
+}
+
+func TestGatewayClassReconcilerMapping(t *testing.T) {
+	gatewayClass := func(name, controllerName, paramsName string) gatewayapiv1.GatewayClass {
+		return gatewayapiv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: gatewayapiv1.GatewayClassSpec{
+				ControllerName: gatewayapiv1.GatewayController(controllerName),
+				ParametersRef: &gatewayapiv1.ParametersReference{
+					Group:     "",
+					Kind:      "ConfigMap",
+					Name:      paramsName,
+					Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+				},
+			},
+		}
+	}
+	r := &GatewayClassReconciler{
+		Scheme: testutil.GetValidTestScheme(),
+		Client: fake.NewClientBuilder().
+			WithScheme(testutil.GetValidTestScheme()).
+			WithLists(&gatewayapiv1.GatewayClassList{
+				Items: []gatewayapiv1.GatewayClass{
+					gatewayClass("class-a", ControllerName, "params-a"),
+					gatewayClass("class-b", ControllerName, "params-b"),
+					gatewayClass("other", "example.com/some-other-controller", "params-a"),
+				},
+			}).
+			WithIndex(&gatewayapiv1.GatewayClass{}, GatewayClassParamsIndex, gatewayClassParams).
+			Build(),
+	}
+	requestNames := func(requests []reconcile.Request) []string {
+		names := []string{}
+		for _, request := range requests {
+			names = append(names, request.Name)
+		}
+		return names
+	}
+
+	params := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "params-a", Namespace: testutil.Namespace}}
+	if names := requestNames(r.paramsToGatewayClasses(context.TODO(), params)); len(names) != 1 || names[0] != "class-a" {
+		t.Errorf("expected only class-a to be enqueued but got %v", names)
+	}
+
+	crd := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "gateways.gateway.networking.k8s.io"}}
+	if names := requestNames(r.crdToGatewayClasses(context.TODO(), crd)); len(names) != 2 || names[0] != "class-a" || names[1] != "class-b" {
+		t.Errorf("expected the classes of this controller to be enqueued but got %v", names)
+	}
+
+	otherCRD := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "certificates.cert-manager.io"}}
+	if names := requestNames(r.crdToGatewayClasses(context.TODO(), otherCRD)); len(names) != 0 {
+		t.Errorf("expected no classes to be enqueued for an unrelated CRD but got %v", names)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The MultiCluster Traffic Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+)
+
+// GatewayClassParametersReconciler reports the GatewayClasses referencing a GatewayClassParameters object
+type GatewayClassParametersReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=kuadrant.io,resources=gatewayclassparameters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kuadrant.io,resources=gatewayclassparameters/status,verbs=get;update;patch
+
+func (r *GatewayClassParametersReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx)
+
+	previous := &v1alpha1.GatewayClassParameters{}
+	if err := r.Client.Get(ctx, req.NamespacedName, previous); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	gatewayClasses := &gatewayapiv1.GatewayClassList{}
+	if err := r.Client.List(ctx, gatewayClasses); err != nil {
+		return ctrl.Result{}, err
+	}
+	key := paramsKey(v1alpha1.GroupVersion.WithKind("GatewayClassParameters").GroupKind(), previous.Namespace, previous.Name)
+	referencing := []string{}
+	for _, gatewayClass := range gatewayClasses.Items {
+		if gatewayClass.Spec.ControllerName == ControllerName && slice.ContainsString(gatewayClassParams(&gatewayClass), key) {
+			referencing = append(referencing, gatewayClass.Name)
+		}
+	}
+	sort.Strings(referencing)
+
+	parameters := previous.DeepCopy()
+	parameters.Status.ObservedGeneration = previous.Generation
+	parameters.Status.GatewayClasses = nil
+	if len(referencing) > 0 {
+		parameters.Status.GatewayClasses = referencing
+	}
+	if reflect.DeepEqual(previous.Status, parameters.Status) {
+		return ctrl.Result{}, nil
+	}
+
+	log.V(3).Info("updating gateway class parameters status", "gatewayClasses", parameters.Status.GatewayClasses)
+	if err := r.Status().Update(ctx, parameters); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// gatewayClassToParameters maps a GatewayClass to the GatewayClassParameters it references. As the map function is
+// called with both the old and the new object on updates, parameters that are no longer referenced are also enqueued
+func gatewayClassToParameters(_ context.Context, o client.Object) []reconcile.Request {
+	gatewayClass, ok := o.(*gatewayapiv1.GatewayClass)
+	if !ok {
+		return nil
+	}
+	paramsRef := gatewayClass.Spec.ParametersRef
+	if paramsRef == nil || string(paramsRef.Group) != v1alpha1.GroupVersion.Group || paramsRef.Kind != "GatewayClassParameters" || paramsRef.Namespace == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: string(*paramsRef.Namespace), Name: paramsRef.Name}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayClassParametersReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.GatewayClassParameters{}).
+		Watches(&gatewayapiv1.GatewayClass{}, handler.EnqueueRequestsFromMapFunc(gatewayClassToParameters)).
+		Complete(r)
+}
@@ -0,0 +1,123 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestGatewayClassParametersReconciler_Reconcile(t *testing.T) {
+	gatewayClass := func(name, controllerName, paramsName string) gatewayapiv1.GatewayClass {
+		return gatewayapiv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: gatewayapiv1.GatewayClassSpec{
+				ControllerName: gatewayapiv1.GatewayController(controllerName),
+				ParametersRef: &gatewayapiv1.ParametersReference{
+					Group:     "kuadrant.io",
+					Kind:      "GatewayClassParameters",
+					Name:      paramsName,
+					Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+				},
+			},
+		}
+	}
+	testCases := []struct {
+		name   string
+		status v1alpha1.GatewayClassParametersStatus
+		want   []string
+	}{
+		{
+			name: "reports the classes of this controller referencing the parameters",
+			want: []string{"class-a", "class-b"},
+		},
+		{
+			name:   "removes classes no longer referencing the parameters",
+			status: v1alpha1.GatewayClassParametersStatus{GatewayClasses: []string{"class-a", "class-b", "class-c"}},
+			want:   []string{"class-a", "class-b"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := testutil.GetValidTestClient(
+				&gatewayapiv1.GatewayClassList{
+					Items: []gatewayapiv1.GatewayClass{
+						gatewayClass("class-b", ControllerName, "params"),
+						gatewayClass("class-a", ControllerName, "params"),
+						gatewayClass("class-c", ControllerName, "other-params"),
+						gatewayClass("class-d", "example.com/some-other-controller", "params"),
+					},
+				},
+				&v1alpha1.GatewayClassParametersList{
+					Items: []v1alpha1.GatewayClassParameters{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: testutil.Namespace, Generation: 2},
+							Status:     testCase.status,
+						},
+					},
+				},
+			)
+			r := &GatewayClassParametersReconciler{Client: c, Scheme: testutil.GetValidTestScheme()}
+			if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKey{Namespace: testutil.Namespace, Name: "params"}}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			parameters := &v1alpha1.GatewayClassParameters{}
+			if err := c.Get(context.TODO(), client.ObjectKey{Namespace: testutil.Namespace, Name: "params"}, parameters); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(parameters.Status.GatewayClasses, testCase.want) {
+				t.Errorf("expected gateway classes %v but got %v", testCase.want, parameters.Status.GatewayClasses)
+			}
+			if parameters.Status.ObservedGeneration != 2 {
+				t.Errorf("expected observed generation 2 but got %d", parameters.Status.ObservedGeneration)
+			}
+		})
+	}
+}
+
+func TestGatewayClassToParameters(t *testing.T) {
+	testCases := []struct {
+		name      string
+		paramsRef *gatewayapiv1.ParametersReference
+		want      int
+	}{
+		{
+			name: "references GatewayClassParameters",
+			paramsRef: &gatewayapiv1.ParametersReference{
+				Group:     "kuadrant.io",
+				Kind:      "GatewayClassParameters",
+				Name:      "params",
+				Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+			},
+			want: 1,
+		},
+		{
+			name: "references a ConfigMap",
+			paramsRef: &gatewayapiv1.ParametersReference{
+				Kind:      "ConfigMap",
+				Name:      "params",
+				Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+			},
+		},
+		{
+			name: "no parameters",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			gatewayClass := &gatewayapiv1.GatewayClass{Spec: gatewayapiv1.GatewayClassSpec{ParametersRef: testCase.paramsRef}}
+			if requests := gatewayClassToParameters(context.TODO(), gatewayClass); len(requests) != testCase.want {
+				t.Errorf("expected %d requests but got %v", testCase.want, requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	gatewayNamespaceLabel = "gateway_namespace"
+	gatewayNameLabel      = "gateway"
+	clusterLabel          = "cluster"
+	secretLabel           = "secret"
+)
+
+var (
+	certificateExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mgc_gateway_tls_certificate_expiry_timestamp_seconds",
+			Help: "Expiry time of the TLS certificate applied in the cluster for a gateway listener secret",
+		},
+		[]string{gatewayNamespaceLabel, gatewayNameLabel, clusterLabel, secretLabel},
+	)
+	certificateMismatch = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mgc_gateway_tls_certificate_mismatch",
+			Help: "1 when the TLS certificate applied in the cluster differs from the certificate in the hub, 0 otherwise",
+		},
+		[]string{gatewayNamespaceLabel, gatewayNameLabel, clusterLabel, secretLabel},
+	)
+	targetClusters = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mgc_gateway_target_clusters",
+			Help: "Number of clusters the placement of a gateway targets",
+		},
+		[]string{gatewayNamespaceLabel, gatewayNameLabel},
+	)
+	placedClusters = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "mgc_gateway_placed_clusters",
+			Help: "Number of clusters a gateway is placed and applied on",
+		},
+		[]string{gatewayNamespaceLabel, gatewayNameLabel},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpiry, certificateMismatch, targetClusters, placedClusters)
+}
+
+// recordCertificateMetrics replaces the certificate metrics of the gateway with the given placed certificates
+func recordCertificateMetrics(gateway *gatewayapiv1.Gateway, certificates []placedCertificate) {
+	deleteCertificateMetrics(gateway)
+	for _, certificate := range certificates {
+		if !certificate.reported {
+			continue
+		}
+		labels := prometheus.Labels{
+			gatewayNamespaceLabel: gateway.Namespace,
+			gatewayNameLabel:      gateway.Name,
+			clusterLabel:          certificate.cluster,
+			secretLabel:           certificate.secret,
+		}
+		mismatch := 0.0
+		if certificate.mismatch {
+			mismatch = 1
+		}
+		certificateMismatch.With(labels).Set(mismatch)
+		if !certificate.notAfter.IsZero() {
+			certificateExpiry.With(labels).Set(float64(certificate.notAfter.Unix()))
+		}
+	}
+}
+
+// deleteCertificateMetrics removes the certificate metrics of the gateway
+func deleteCertificateMetrics(gateway *gatewayapiv1.Gateway) {
+	labels := prometheus.Labels{
+		gatewayNamespaceLabel: gateway.Namespace,
+		gatewayNameLabel:      gateway.Name,
+	}
+	certificateExpiry.DeletePartialMatch(labels)
+	certificateMismatch.DeletePartialMatch(labels)
+}
+
+// recordPlacementMetrics records the number of clusters the gateway targets and is placed on
+func recordPlacementMetrics(gateway *gatewayapiv1.Gateway, targets, placed int) {
+	labels := prometheus.Labels{
+		gatewayNamespaceLabel: gateway.Namespace,
+		gatewayNameLabel:      gateway.Name,
+	}
+	targetClusters.With(labels).Set(float64(targets))
+	placedClusters.With(labels).Set(float64(placed))
+}
+
+// deletePlacementMetrics removes the placement metrics of the gateway
+func deletePlacementMetrics(gateway *gatewayapiv1.Gateway) {
+	labels := prometheus.Labels{
+		gatewayNamespaceLabel: gateway.Namespace,
+		gatewayNameLabel:      gateway.Name,
+	}
+	targetClusters.Delete(labels)
+	placedClusters.Delete(labels)
+}
@@ -0,0 +1,61 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestPlacementMetrics(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "test"}}
+
+	recordPlacementMetrics(gateway, 3, 2)
+	if targets := promtestutil.ToFloat64(targetClusters.WithLabelValues("test", "metrics")); targets != 3 {
+		t.Errorf("expected 3 target clusters but got %v", targets)
+	}
+	if placed := promtestutil.ToFloat64(placedClusters.WithLabelValues("test", "metrics")); placed != 2 {
+		t.Errorf("expected 2 placed clusters but got %v", placed)
+	}
+
+	deletePlacementMetrics(gateway)
+	if targetClusters.DeleteLabelValues("test", "metrics") || placedClusters.DeleteLabelValues("test", "metrics") {
+		t.Errorf("expected the placement metrics of the gateway to be deleted")
+	}
+}
+
+func TestUnmanagedGatewayPlacementMetrics(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "test", Finalizers: []string{GatewayFinalizer}},
+		Spec:       gatewayapiv1.GatewaySpec{GatewayClassName: "other"},
+	}
+	class := &gatewayapiv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec:       gatewayapiv1.GatewayClassSpec{ControllerName: "example.com/other-controller"},
+	}
+	r := &GatewayReconciler{
+		Client: testutil.GetValidTestClient(
+			&gatewayapiv1.GatewayList{Items: []gatewayapiv1.Gateway{*gateway}},
+			&gatewayapiv1.GatewayClassList{Items: []gatewayapiv1.GatewayClass{*class}},
+		),
+		Scheme: testutil.GetValidTestScheme(),
+	}
+
+	// the gateway was placed before its class moved to another controller
+	recordPlacementMetrics(gateway, 3, 2)
+	if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "test", Name: "metrics"}}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if targetClusters.DeleteLabelValues("test", "metrics") || placedClusters.DeleteLabelValues("test", "metrics") {
+		t.Errorf("expected the placement metrics of the gateway with an unmanaged class to be deleted")
+	}
+}
@@ -2,34 +2,63 @@ package gateway
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
+	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
 )
 
-type Params struct {
-	// DownstreamClass specifies what GatewayClassName to set in the
-	// downstream clusters. For example:
-	DownstreamClass string `json:"downstreamClass,omitempty"`
+const (
+	// ParamsVersionV1 is the version of the format of the params field of a ConfigMap
+	ParamsVersionV1 = "v1"
+
+	// DefaultDownstreamNamespace is the template of the namespace the gateway is placed in on the spokes when the
+	// parameters don't set one
+	DefaultDownstreamNamespace = "kuadrant-{{.Namespace}}"
+)
+
+// supportedParamsVersions are the versions of the parameters format that can be decoded. An empty version is
+// decoded as the first version so that existing parameters keep working
+var supportedParamsVersions = []string{ParamsVersionV1}
+
+// Params are the parameters of a GatewayClass. They hold the spec of a GatewayClassParameters, or the params field
+// of a ConfigMap which has the same fields plus the version of the format
+type Params v1alpha1.GatewayClassParametersSpec
 
-	// PoliciesToSync specifies a listof Policy GVRs that will be watched
-	// in the hub and synced to the spokes
-	PoliciesToSync []ParamsGroupVersionResource `json:"experimentalPolicySync,omitempty"`
+// configMapParams is the format of the params field of a ConfigMap
+type configMapParams struct {
+	// Version of the parameters format. Defaults to v1
+	Version string `json:"version,omitempty"`
+
+	v1alpha1.GatewayClassParametersSpec `json:",inline"`
 }
 
-type ParamsGroupVersionResource struct {
-	Group    string `json:"group"`
-	Version  string `json:"version"`
-	Resource string `json:"resource"`
+const (
+	// MergePatchType is a JSON merge patch (RFC 7386). It is the default patch type of a ClusterOverride
+	MergePatchType = "merge"
+	// JSONPatchType is a JSON patch (RFC 6902)
+	JSONPatchType = "json"
+)
+
+// downstreamNamespaceData is the data the DownstreamNamespace template is executed with
+type downstreamNamespaceData struct {
+	Namespace string
+	Name      string
 }
 
-func (gvr ParamsGroupVersionResource) ToGroupVersionResource() schema.GroupVersionResource {
+func policyGroupVersionResource(gvr v1alpha1.PolicyGroupVersionResource) schema.GroupVersionResource {
 	return schema.GroupVersionResource{
 		Group:    gvr.Group,
 		Version:  gvr.Version,
@@ -41,6 +70,58 @@ func (p *Params) GetDownstreamClass() string {
 	return p.DownstreamClass
 }
 
+// GetDownstreamNamespace returns the namespace the gateway is placed in on the spokes
+func (p *Params) GetDownstreamNamespace(gateway *gatewayapiv1.Gateway) (string, error) {
+	namespaceTemplate := DefaultDownstreamNamespace
+	if p != nil && p.DownstreamNamespace != "" {
+		namespaceTemplate = p.DownstreamNamespace
+	}
+	tmpl, err := template.New("downstreamNamespace").Option("missingkey=error").Parse(namespaceTemplate)
+	if err != nil {
+		return "", &InvalidParamsError{fmt.Sprintf("invalid downstreamNamespace %s: %v", namespaceTemplate, err)}
+	}
+	namespace := &strings.Builder{}
+	if err := tmpl.Execute(namespace, downstreamNamespaceData{Namespace: gateway.Namespace, Name: gateway.Name}); err != nil {
+		return "", &InvalidParamsError{fmt.Sprintf("invalid downstreamNamespace %s: %v", namespaceTemplate, err)}
+	}
+	if errs := validation.IsDNS1123Label(namespace.String()); len(errs) > 0 {
+		return "", &InvalidParamsError{fmt.Sprintf("invalid downstream namespace %s for gateway %s/%s: %s", namespace.String(), gateway.Namespace, gateway.Name, strings.Join(errs, ", "))}
+	}
+	return namespace.String(), nil
+}
+
+// HasManagedDownstreamNamespace is true when the gateway is placed in the default namespace owned by the controller
+func (p *Params) HasManagedDownstreamNamespace() bool {
+	return p == nil || p.DownstreamNamespace == ""
+}
+
+// validate checks the parameters that can be verified without a gateway
+func (p *Params) validate() error {
+	if p.DownstreamNamespace != "" {
+		if _, err := p.GetDownstreamNamespace(&gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "name"}}); err != nil {
+			return err
+		}
+	}
+	if p.DownstreamMetadata != nil {
+		if err := validateDownstreamMetadata(p.DownstreamMetadata); err != nil {
+			return &InvalidParamsError{fmt.Sprintf("invalid downstreamMetadata: %v", err)}
+		}
+	}
+	if err := validateDownstreamClasses(p.DownstreamClasses); err != nil {
+		return &InvalidParamsError{fmt.Sprintf("invalid downstreamClasses: %v", err)}
+	}
+	if err := validateClusterOverrides(p.ClusterOverrides); err != nil {
+		return &InvalidParamsError{fmt.Sprintf("invalid clusterOverrides: %v", err)}
+	}
+	if err := p.validateRequirements(); err != nil {
+		return &InvalidParamsError{err.Error()}
+	}
+	if err := p.validateAddresses(); err != nil {
+		return &InvalidParamsError{err.Error()}
+	}
+	return nil
+}
+
 var defaultParams Params = Params{
 	DownstreamClass: "istio",
 }
@@ -64,7 +145,8 @@ func IsInvalidParamsError(err error) (is bool) {
 type ParamsResolver func(context.Context, client.Client, gatewayapiv1.ParametersReference) (*Params, error)
 
 var paramsResolvers = map[schema.GroupKind]ParamsResolver{
-	{Group: corev1.GroupName, Kind: "ConfigMap"}: fromNamespacedObject(fromConfigMap),
+	{Group: corev1.GroupName, Kind: "ConfigMap"}:                         fromNamespacedObject(fromConfigMap),
+	{Group: v1alpha1.GroupVersion.Group, Kind: "GatewayClassParameters"}: fromNamespacedObject(fromGatewayClassParameters),
 }
 
 func fromNamespacedObject[T client.Object](getParams func(T) (*Params, error)) ParamsResolver {
@@ -96,12 +178,32 @@ func fromConfigMap(configMap *corev1.ConfigMap) (*Params, error) {
 		return nil, &InvalidParamsError{"Parameters must be defined in \"params\" field of ConfigMap"}
 	}
 
-	result := &Params{}
-	if err := json.Unmarshal([]byte(paramsRaw), result); err != nil {
+	// The params can be JSON or YAML. Unknown fields are rejected so that misspelled fields are reported
+	decoded := &configMapParams{}
+	if err := yaml.UnmarshalStrict([]byte(paramsRaw), decoded); err != nil {
 		return nil, &InvalidParamsError{fmt.Sprintf("Failed to unmarshal params: %v", err)}
 	}
+	if decoded.Version != "" && !slice.ContainsString(supportedParamsVersions, decoded.Version) {
+		return nil, &InvalidParamsError{fmt.Sprintf("Unsupported params version %s. Must be one of [%s]", decoded.Version, strings.Join(supportedParamsVersions, ","))}
+	}
+	return newParams(decoded.GatewayClassParametersSpec)
+}
+
+func fromGatewayClassParameters(parameters *v1alpha1.GatewayClassParameters) (*Params, error) {
+	return newParams(*parameters.Spec.DeepCopy())
+}
 
-	return result, nil
+// newParams defaults and validates the parameters of the spec. The downstream class defaults to the one of the
+// GatewayClassParameters CRD so that both sources of parameters resolve the same way
+func newParams(spec v1alpha1.GatewayClassParametersSpec) (*Params, error) {
+	result := Params(spec)
+	if result.DownstreamClass == "" {
+		result.DownstreamClass = defaultParams.DownstreamClass
+	}
+	if err := result.validate(); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 func getParams(ctx context.Context, c client.Client, gatewayClassName string) (*Params, error) {
@@ -15,6 +15,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
 	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
 )
 
@@ -125,6 +126,183 @@ func TestGetParams(t *testing.T) {
 			},
 			assertParams: assertError(IsInvalidParamsError),
 		},
+		{
+			name: "ConfigMap with YAML params",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "",
+						Kind:      "ConfigMap",
+						Name:      testutil.DummyCRName,
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+				Data: map[string]string{
+					"params": "version: v1\ndownstreamClass: eg\nexperimentalPolicySync:\n- group: kuadrant.io\n  version: v1beta2\n  resource: authpolicies\n",
+				},
+			},
+			assertParams: and(
+				noError,
+				paramsEqual(Params{
+					DownstreamClass: "eg",
+					PoliciesToSync: []v1alpha1.PolicyGroupVersionResource{
+						{Group: "kuadrant.io", Version: "v1beta2", Resource: "authpolicies"},
+					},
+				}),
+			),
+		},
+		{
+			name: "ConfigMap without downstreamClass",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "",
+						Kind:      "ConfigMap",
+						Name:      testutil.DummyCRName,
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+				Data: map[string]string{
+					"params": "downstreamNamespace: team-a\n",
+				},
+			},
+			assertParams: and(
+				noError,
+				paramsEqual(Params{
+					DownstreamClass:     "istio",
+					DownstreamNamespace: "team-a",
+				}),
+			),
+		},
+		{
+			name: "ConfigMap with unknown field",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "",
+						Kind:      "ConfigMap",
+						Name:      testutil.DummyCRName,
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+				Data: map[string]string{
+					"params": `{"downstreamClas": "istio"}`,
+				},
+			},
+			assertParams: assertError(IsInvalidParamsError),
+		},
+		{
+			name: "ConfigMap with unsupported version",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "",
+						Kind:      "ConfigMap",
+						Name:      testutil.DummyCRName,
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+				Data: map[string]string{
+					"params": `{"version": "v2", "downstreamClass": "istio"}`,
+				},
+			},
+			assertParams: assertError(IsInvalidParamsError),
+		},
+		{
+			name: "ConfigMap with invalid downstream metadata",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "",
+						Kind:      "ConfigMap",
+						Name:      testutil.DummyCRName,
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+				Data: map[string]string{
+					"params": `{"downstreamClass": "istio", "downstreamMetadata": {"labels": {"deny": [""]}}}`,
+				},
+			},
+			assertParams: assertError(IsInvalidParamsError),
+		},
+		{
+			name: "ConfigMap with invalid cluster override",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "",
+						Kind:      "ConfigMap",
+						Name:      testutil.DummyCRName,
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+				Data: map[string]string{
+					"params": `
+downstreamClass: istio
+clusterOverrides:
+- name: internal-lb
+  patch:
+    spec:
+      addresses: []
+`,
+				},
+			},
+			assertParams: assertError(IsInvalidParamsError),
+		},
 		{
 			name: "Missing namespace",
 			gatewayClass: &gatewayapiv1.GatewayClass{
@@ -171,6 +349,106 @@ func TestGetParams(t *testing.T) {
 			},
 			assertParams: assertError(IsInvalidParamsError),
 		},
+		{
+			name: "GatewayClassParameters found",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "kuadrant.io",
+						Kind:      "GatewayClassParameters",
+						Name:      testutil.DummyCRName,
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &v1alpha1.GatewayClassParameters{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+				Spec: v1alpha1.GatewayClassParametersSpec{
+					DownstreamClass: "eg",
+					PoliciesToSync: []v1alpha1.PolicyGroupVersionResource{
+						{Group: "kuadrant.io", Version: "v1beta2", Resource: "authpolicies"},
+					},
+					DownstreamMetadata: &v1alpha1.DownstreamMetadata{
+						Labels: v1alpha1.MetadataRules{
+							Deny:   []string{"kuadrant.io/*"},
+							Inject: map[string]string{"istio.io/rev": "canary"},
+						},
+					},
+				},
+			},
+			assertParams: and(
+				noError,
+				paramsEqual(Params{
+					DownstreamClass: "eg",
+					PoliciesToSync: []v1alpha1.PolicyGroupVersionResource{
+						{Group: "kuadrant.io", Version: "v1beta2", Resource: "authpolicies"},
+					},
+					DownstreamMetadata: &v1alpha1.DownstreamMetadata{
+						Labels: v1alpha1.MetadataRules{
+							Deny:   []string{"kuadrant.io/*"},
+							Inject: map[string]string{"istio.io/rev": "canary"},
+						},
+					},
+				}),
+			),
+		},
+		{
+			name: "GatewayClassParameters found. Uses default downstream class",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "kuadrant.io",
+						Kind:      "GatewayClassParameters",
+						Name:      testutil.DummyCRName,
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &v1alpha1.GatewayClassParameters{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+			},
+			assertParams: and(
+				noError,
+				paramsEqual(Params{
+					DownstreamClass: "istio",
+				}),
+			),
+		},
+		{
+			name: "GatewayClassParameters not found",
+			gatewayClass: &gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: gatewayapiv1.GatewayClassSpec{
+					ParametersRef: &gatewayapiv1.ParametersReference{
+						Group:     "kuadrant.io",
+						Kind:      "GatewayClassParameters",
+						Name:      "test-params-no-exist",
+						Namespace: testutil.Pointer(gatewayapiv1.Namespace(testutil.Namespace)),
+					},
+				},
+			},
+			paramsObj: &v1alpha1.GatewayClassParameters{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutil.DummyCRName,
+					Namespace: testutil.Namespace,
+				},
+			},
+			assertParams: assertError(IsInvalidParamsError),
+		},
 		{
 			name: "Unsupported GroupKind",
 
@@ -204,6 +482,9 @@ func TestGetParams(t *testing.T) {
 	if err := corev1.AddToScheme(scheme); err != nil {
 		t.Fatalf("unexpected error building scheme: %v", err)
 	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unexpected error building scheme: %v", err)
+	}
 
 	for _, testCase := range cases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -263,3 +544,66 @@ func paramsEqual(expected Params) func(*Params, error) error {
 		return fmt.Errorf("unexpected params. Expected %v, got %v", expected, got)
 	}
 }
+
+func TestGetDownstreamNamespace(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod-web", Namespace: "multi-cluster-gateways"},
+	}
+	cases := []struct {
+		name    string
+		params  *Params
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "default namespace without params",
+			params: nil,
+			want:   "kuadrant-multi-cluster-gateways",
+		},
+		{
+			name:   "default namespace when not set",
+			params: &Params{},
+			want:   "kuadrant-multi-cluster-gateways",
+		},
+		{
+			name:   "fixed namespace",
+			params: &Params{DownstreamNamespace: "team-a"},
+			want:   "team-a",
+		},
+		{
+			name:   "templated namespace",
+			params: &Params{DownstreamNamespace: "{{.Namespace}}-{{.Name}}"},
+			want:   "multi-cluster-gateways-prod-web",
+		},
+		{
+			name:    "invalid template",
+			params:  &Params{DownstreamNamespace: "{{.Cluster}}"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid namespace",
+			params:  &Params{DownstreamNamespace: "Team_A"},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := testCase.params.GetDownstreamNamespace(gateway)
+			if testCase.wantErr {
+				if !IsInvalidParamsError(err) {
+					t.Fatalf("expected an invalid params error but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != testCase.want {
+				t.Errorf("expected namespace %s but got %s", testCase.want, got)
+			}
+			if managed := testCase.params.HasManagedDownstreamNamespace(); managed != (testCase.params == nil || testCase.params.DownstreamNamespace == "") {
+				t.Errorf("unexpected managed namespace %v", managed)
+			}
+		})
+	}
+}
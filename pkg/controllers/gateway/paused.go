@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
+)
+
+const (
+	// PausedAnnotation set to "true" on a gateway stops the controller from changing where and how the gateway is
+	// placed. The status of the gateway is still reported and a deleted gateway is still removed from the clusters
+	PausedAnnotation = LabelPrefix + "paused"
+
+	// PausedConditionType reports that the placement of the gateway is paused
+	PausedConditionType = LabelPrefix + "Paused"
+	PausedReason        = "Paused"
+)
+
+// isPaused returns whether the placement of the gateway is paused
+func isPaused(gateway *gatewayapiv1.Gateway) bool {
+	return !isDeleting(gateway) && metadata.GetAnnotation(gateway, PausedAnnotation) == "true"
+}
+
+// pausedDownstream returns the clusters the paused gateway is placed on without changing its placement. The gateway
+// is programmed when it is placed on all the targeted clusters
+func (r *GatewayReconciler) pausedDownstream(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway, invalidListeners map[gatewayapiv1.SectionName]metav1.Condition) (bool, metav1.ConditionStatus, []string, map[gatewayapiv1.SectionName]metav1.Condition, error) {
+	log := crlog.FromContext(ctx)
+	log.Info("gateway placement paused", "gateway", upstreamGateway.Name, "namespace", upstreamGateway.Namespace)
+	placed, err := r.Placement.GetPlacedClusters(ctx, upstreamGateway)
+	if err != nil {
+		return false, metav1.ConditionUnknown, []string{}, invalidListeners, fmt.Errorf("failed to get placed clusters : %s", err)
+	}
+	targets, err := r.Placement.GetClusters(ctx, upstreamGateway)
+	if err != nil {
+		log.V(3).Info("failed to get target clusters of paused gateway", "error", err)
+		return false, metav1.ConditionUnknown, sets.List(placed), invalidListeners, nil
+	}
+	if placed.Equal(sets.New[string]().Union(targets)) && placed.Len() > 0 {
+		return false, metav1.ConditionTrue, sets.List(placed), invalidListeners, nil
+	}
+	return false, metav1.ConditionUnknown, sets.List(placed), invalidListeners, nil
+}
+
+// buildPausedCondition reports that the placement of the gateway is paused, nil is returned when it isn't
+func buildPausedCondition(gateway *gatewayapiv1.Gateway) *metav1.Condition {
+	if !isPaused(gateway) {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:               PausedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             PausedReason,
+		Message:            fmt.Sprintf("placement paused by the %s annotation, the ManifestWorks of the gateway are not changed", PausedAnnotation),
+		ObservedGeneration: gateway.Generation,
+	}
+}
@@ -0,0 +1,30 @@
+//go:build unit
+
+package gateway
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestBuildPausedCondition(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 3}}
+	if condition := buildPausedCondition(gateway); condition != nil {
+		t.Errorf("expected no condition without the paused annotation but got %v", condition)
+	}
+	gateway.Annotations = map[string]string{PausedAnnotation: "true", PlanAnnotation: "true"}
+	condition := buildPausedCondition(gateway)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.ObservedGeneration != 3 {
+		t.Errorf("expected a true condition for the paused gateway but got %v", condition)
+	}
+	if isPlanning(gateway) {
+		t.Errorf("did not expect a paused gateway to be planned")
+	}
+	now := metav1.Now()
+	gateway.DeletionTimestamp = &now
+	if condition := buildPausedCondition(gateway); condition != nil {
+		t.Errorf("expected no condition for a deleted gateway but got %v", condition)
+	}
+}
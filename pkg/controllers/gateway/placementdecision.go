@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"context"
+
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+// GatewayPlacementIndex indexes gateways by the placement referenced in their placement label
+const GatewayPlacementIndex = "metadata.labels.placement"
+
+// gatewayPlacement is the GatewayPlacementIndex extractor
+func gatewayPlacement(o client.Object) []string {
+	name := o.GetLabels()[placement.OCMPlacementLabel]
+	if name == "" {
+		return nil
+	}
+	return []string{name}
+}
+
+// placementDecisionToGateways maps a PlacementDecision to the gateways in its namespace that reference its placement
+func (r *GatewayReconciler) placementDecisionToGateways(ctx context.Context, o client.Object) []reconcile.Request {
+	log := crlog.FromContext(ctx)
+	placementName := o.GetLabels()[placement.OCMPlacementLabel]
+	if placementName == "" {
+		return nil
+	}
+	gateways := &gatewayapiv1.GatewayList{}
+	if err := r.Client.List(ctx, gateways, client.InNamespace(o.GetNamespace()), client.MatchingFields{GatewayPlacementIndex: placementName}); err != nil {
+		log.Error(err, "failed to list gateways for placement decision", "placementdecision", client.ObjectKeyFromObject(o))
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, gateway := range gateways.Items {
+		log.V(3).Info("enqueuing gateway based on placement decision change", "gateway", gateway.Name, "namespace", gateway.Namespace)
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+	}
+	return requests
+}
+
+// placementDecisionChangedPredicate filters out the PlacementDecision updates that do not change the decided clusters
+func placementDecisionChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldDecision, ok := e.ObjectOld.(*clusterv1beta1.PlacementDecision)
+			if !ok {
+				return true
+			}
+			newDecision, ok := e.ObjectNew.(*clusterv1beta1.PlacementDecision)
+			if !ok {
+				return true
+			}
+			return !decisionClusters(oldDecision).Equal(decisionClusters(newDecision)) ||
+				oldDecision.Labels[placement.OCMPlacementLabel] != newDecision.Labels[placement.OCMPlacementLabel]
+		},
+	}
+}
+
+func decisionClusters(decision *clusterv1beta1.PlacementDecision) sets.Set[string] {
+	clusters := sets.New[string]()
+	for _, d := range decision.Status.Decisions {
+		clusters.Insert(d.ClusterName)
+	}
+	return clusters
+}
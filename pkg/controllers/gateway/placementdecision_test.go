@@ -0,0 +1,109 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func buildTestPlacementDecision(namespace, placementName string, clusters ...string) *clusterv1beta1.PlacementDecision {
+	decision := &clusterv1beta1.PlacementDecision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      placementName + "-decision-1",
+			Namespace: namespace,
+			Labels: map[string]string{
+				placement.OCMPlacementLabel: placementName,
+			},
+		},
+	}
+	for _, cluster := range clusters {
+		decision.Status.Decisions = append(decision.Status.Decisions, clusterv1beta1.ClusterDecision{ClusterName: cluster})
+	}
+	return decision
+}
+
+func TestPlacementDecisionToGateways(t *testing.T) {
+	placedGateway := func(name, namespace, placementName string) gatewayapiv1.Gateway {
+		return gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					placement.OCMPlacementLabel: placementName,
+				},
+			},
+		}
+	}
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(testutil.GetValidTestScheme()).
+			WithLists(&gatewayapiv1.GatewayList{
+				Items: []gatewayapiv1.Gateway{
+					placedGateway("placed", testutil.Namespace, testutil.Placement),
+					placedGateway("other-placement", testutil.Namespace, "other"),
+					placedGateway("other-namespace", "other", testutil.Placement),
+					{ObjectMeta: metav1.ObjectMeta{Name: "not-placed", Namespace: testutil.Namespace}},
+				},
+			}).
+			WithIndex(&gatewayapiv1.Gateway{}, GatewayPlacementIndex, gatewayPlacement).
+			Build(),
+	}
+
+	requests := r.placementDecisionToGateways(context.TODO(), buildTestPlacementDecision(testutil.Namespace, testutil.Placement, "a"))
+	if len(requests) != 1 || requests[0].Name != "placed" || requests[0].Namespace != testutil.Namespace {
+		t.Errorf("expected only the gateway referencing the placement to be enqueued but got %v", requests)
+	}
+
+	unlabelled := buildTestPlacementDecision(testutil.Namespace, testutil.Placement, "a")
+	unlabelled.Labels = nil
+	if requests := r.placementDecisionToGateways(context.TODO(), unlabelled); len(requests) != 0 {
+		t.Errorf("expected no gateways enqueued for a decision without a placement label but got %v", requests)
+	}
+}
+
+func TestPlacementDecisionChangedPredicate(t *testing.T) {
+	cases := []struct {
+		name     string
+		old      *clusterv1beta1.PlacementDecision
+		new      *clusterv1beta1.PlacementDecision
+		expected bool
+	}{
+		{
+			name:     "same clusters in another order",
+			old:      buildTestPlacementDecision(testutil.Namespace, testutil.Placement, "a", "b"),
+			new:      buildTestPlacementDecision(testutil.Namespace, testutil.Placement, "b", "a"),
+			expected: false,
+		},
+		{
+			name:     "cluster added",
+			old:      buildTestPlacementDecision(testutil.Namespace, testutil.Placement, "a"),
+			new:      buildTestPlacementDecision(testutil.Namespace, testutil.Placement, "a", "b"),
+			expected: true,
+		},
+		{
+			name:     "cluster removed",
+			old:      buildTestPlacementDecision(testutil.Namespace, testutil.Placement, "a", "b"),
+			new:      buildTestPlacementDecision(testutil.Namespace, testutil.Placement, "a"),
+			expected: true,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := placementDecisionChangedPredicate().Update(event.UpdateEvent{ObjectOld: testCase.old, ObjectNew: testCase.new})
+			if got != testCase.expected {
+				t.Errorf("expected %v but got %v", testCase.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+const (
+	// PlanAnnotation set to "true" on a gateway stops the controller from changing the ManifestWorks of the gateway.
+	// The changes placing the gateway would make are published in the plan ConfigMap instead
+	PlanAnnotation = LabelPrefix + "plan"
+	// PlanKey is the key of the plan ConfigMap holding the serialized plan
+	PlanKey = "plan"
+	// PlanSuffix is appended to the gateway name to build the name of the plan ConfigMap
+	PlanSuffix = "-plan"
+
+	// PlanPublishedConditionType reports that the placement of the gateway is held while its plan is reviewed
+	PlanPublishedConditionType = LabelPrefix + "PlanPublished"
+	PlanPublishedReason        = "PlanPublished"
+
+	// maxPlanSize leaves room for the metadata of the ConfigMap under the 1MiB limit of an object
+	maxPlanSize = 1000 * 1024
+)
+
+// GatewayPlan is the plan of a gateway published for review
+type GatewayPlan struct {
+	// Generation of the gateway the plan is made for
+	Generation int64 `json:"generation"`
+	// Add are the clusters the gateway would be placed on
+	Add []string `json:"add"`
+	// Update are the clusters the ManifestWork of the gateway would be changed on
+	Update []string `json:"update"`
+	// Remove are the clusters the gateway would be removed from
+	Remove []string `json:"remove"`
+	// Refuse are the targeted clusters the gateway would not be placed on
+	Refuse []string `json:"refuse"`
+	// ManifestsOmitted is true when the rendered manifests are left out of the clusters to fit in the ConfigMap
+	ManifestsOmitted bool `json:"manifestsOmitted,omitempty"`
+	// Clusters holds the change to each cluster with the rendered manifests of the ManifestWork
+	Clusters []placement.ClusterPlan `json:"clusters"`
+}
+
+func PlanName(gateway *gatewayapiv1.Gateway) string {
+	return gateway.Name + PlanSuffix
+}
+
+// isPlanning returns whether the placement of the gateway is held for review. A paused gateway is not planned
+func isPlanning(gateway *gatewayapiv1.Gateway) bool {
+	return !isDeleting(gateway) && !isPaused(gateway) && metadata.GetAnnotation(gateway, PlanAnnotation) == "true"
+}
+
+func buildGatewayPlan(generation int64, plan *placement.Plan) GatewayPlan {
+	return GatewayPlan{
+		Generation: generation,
+		Add:        plan.ClustersWith(placement.PlanAdd),
+		Update:     plan.ClustersWith(placement.PlanUpdate),
+		Remove:     plan.ClustersWith(placement.PlanRemove),
+		Refuse:     plan.ClustersWith(placement.PlanRefuse),
+		Clusters:   plan.Clusters,
+	}
+}
+
+// serializePlan returns the plan as JSON, leaving the rendered manifests out when the plan would not fit in a
+// ConfigMap
+func serializePlan(gatewayPlan GatewayPlan) ([]byte, error) {
+	serialized, err := json.Marshal(gatewayPlan)
+	if err != nil || len(serialized) <= maxPlanSize {
+		return serialized, err
+	}
+	clusters := []placement.ClusterPlan{}
+	for _, cluster := range gatewayPlan.Clusters {
+		cluster.Manifests = nil
+		clusters = append(clusters, cluster)
+	}
+	gatewayPlan.Clusters = clusters
+	gatewayPlan.ManifestsOmitted = true
+	return json.Marshal(gatewayPlan)
+}
+
+// planDownstream publishes the changes placing the downstream gateway would make instead of placing it. The
+// gateway stays on the clusters it is placed on
+func (r *GatewayReconciler) planDownstream(ctx context.Context, upstreamGateway *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, customise placement.ClusterCustomiser, children []metav1.Object, invalidListeners map[gatewayapiv1.SectionName]metav1.Condition) (bool, metav1.ConditionStatus, []string, map[gatewayapiv1.SectionName]metav1.Condition, error) {
+	plan, err := r.Placement.Plan(ctx, upstreamGateway, downstream, customise, children...)
+	if err != nil {
+		return true, metav1.ConditionFalse, []string{}, invalidListeners, fmt.Errorf("failed to plan gateway placement : %w", err)
+	}
+	// a ConfigMap of the same name created by a user is reported in the PlanPublished condition, the placement is
+	// still held
+	if err := r.reconcilePlan(ctx, upstreamGateway, plan); err != nil && !errors.Is(err, errConfigMapConflict) {
+		return true, metav1.ConditionFalse, []string{}, invalidListeners, err
+	}
+	placed, err := r.Placement.GetPlacedClusters(ctx, upstreamGateway)
+	if err != nil {
+		return false, metav1.ConditionUnknown, []string{}, invalidListeners, fmt.Errorf("failed to get placed clusters : %s", err)
+	}
+	if !plan.HasChanges() && placed.Len() > 0 {
+		return false, metav1.ConditionTrue, sets.List(placed), invalidListeners, nil
+	}
+	return false, metav1.ConditionUnknown, sets.List(placed), invalidListeners, nil
+}
+
+// reconcilePlan publishes the plan of the gateway into a ConfigMap owned by the gateway. A ConfigMap of the same name
+// that is not owned by the gateway is left untouched and errConfigMapConflict is returned
+func (r *GatewayReconciler) reconcilePlan(ctx context.Context, gateway *gatewayapiv1.Gateway, plan *placement.Plan) error {
+	log := crlog.FromContext(ctx)
+	serialized, err := serializePlan(buildGatewayPlan(gateway.Generation, plan))
+	if err != nil {
+		return err
+	}
+	name := PlanName(gateway)
+	result, err := r.reconcileOwnedConfigMap(ctx, gateway, name, PlanKey, string(serialized))
+	if err != nil {
+		return fmt.Errorf("failed to reconcile placement plan %s: %w", name, err)
+	}
+	log.V(3).Info("reconciled gateway placement plan", "configmap", name, "result", result)
+	return nil
+}
+
+// planConflicts returns whether a ConfigMap with the name of the plan of the gateway exists and is not owned by it
+func (r *GatewayReconciler) planConflicts(ctx context.Context, gateway *gatewayapiv1.Gateway) (bool, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: gateway.Namespace, Name: PlanName(gateway)}, configMap); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return !metav1.IsControlledBy(configMap, gateway), nil
+}
+
+// deletePlan removes the plan ConfigMap of a gateway that is no longer planned
+func (r *GatewayReconciler) deletePlan(ctx context.Context, gateway *gatewayapiv1.Gateway) error {
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: gateway.Namespace, Name: PlanName(gateway)}, configMap); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(configMap, gateway) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Client.Delete(ctx, configMap))
+}
+
+// buildPlanPublishedCondition reports that the placement of the gateway is held, and whether the plan could be
+// published. nil is returned when the placement isn't held
+func buildPlanPublishedCondition(gateway *gatewayapiv1.Gateway, conflict bool) *metav1.Condition {
+	if !isPlanning(gateway) {
+		return nil
+	}
+	if conflict {
+		return &metav1.Condition{
+			Type:               PlanPublishedConditionType,
+			Status:             metav1.ConditionFalse,
+			Reason:             ConfigMapConflictReason,
+			Message:            fmt.Sprintf("placement held by the %s annotation, the plan is not published, ConfigMap %s exists and is not owned by the gateway", PlanAnnotation, PlanName(gateway)),
+			ObservedGeneration: gateway.Generation,
+		}
+	}
+	return &metav1.Condition{
+		Type:               PlanPublishedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             PlanPublishedReason,
+		Message:            fmt.Sprintf("placement held by the %s annotation, the plan is published in ConfigMap %s", PlanAnnotation, PlanName(gateway)),
+		ObservedGeneration: gateway.Generation,
+	}
+}
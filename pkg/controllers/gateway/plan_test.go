@@ -0,0 +1,176 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func testPlan() *placement.Plan {
+	return &placement.Plan{Clusters: []placement.ClusterPlan{
+		{Cluster: "c1", Action: placement.PlanUpdate, Manifests: []runtime.RawExtension{{Raw: []byte(`{"kind":"Gateway"}`)}}},
+		{Cluster: "c2", Action: placement.PlanAdd, Manifests: []runtime.RawExtension{{Raw: []byte(`{"kind":"Gateway"}`)}}},
+		{Cluster: "c3", Action: placement.PlanRemove, Reason: "cluster no longer targeted by the placement"},
+		{Cluster: "c4", Action: placement.PlanRefuse, Reason: "cluster refused: missing gateway class istio"},
+		{Cluster: "c5", Action: placement.PlanUnchanged},
+	}}
+}
+
+func TestBuildGatewayPlan(t *testing.T) {
+	gatewayPlan := buildGatewayPlan(3, testPlan())
+	expected := GatewayPlan{
+		Generation: 3,
+		Add:        []string{"c2"},
+		Update:     []string{"c1"},
+		Remove:     []string{"c3"},
+		Refuse:     []string{"c4"},
+		Clusters:   testPlan().Clusters,
+	}
+	if !reflect.DeepEqual(gatewayPlan, expected) {
+		t.Errorf("expected plan %+v but got %+v", expected, gatewayPlan)
+	}
+}
+
+func TestSerializePlan(t *testing.T) {
+	small, err := serializePlan(buildGatewayPlan(1, testPlan()))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !strings.Contains(string(small), `"manifests"`) {
+		t.Errorf("expected the manifests in the plan but got %s", small)
+	}
+
+	large := testPlan()
+	large.Clusters[0].Manifests = []runtime.RawExtension{{Raw: []byte(`"` + strings.Repeat("a", maxPlanSize) + `"`)}}
+	serialized, err := serializePlan(buildGatewayPlan(1, large))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	gatewayPlan := GatewayPlan{}
+	if err := json.Unmarshal(serialized, &gatewayPlan); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !gatewayPlan.ManifestsOmitted || len(serialized) > maxPlanSize {
+		t.Errorf("expected the manifests left out of a large plan but got %d bytes", len(serialized))
+	}
+	if len(gatewayPlan.Clusters) != len(large.Clusters) {
+		t.Errorf("expected the clusters kept in a large plan but got %v", gatewayPlan.Clusters)
+	}
+}
+
+func TestReconcileAndDeletePlan(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   testutil.Namespace,
+			UID:         "test",
+			Generation:  2,
+			Annotations: map[string]string{PlanAnnotation: "true"},
+		},
+	}
+	scheme := testutil.GetValidTestScheme()
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(gateway).Build(),
+		Scheme: scheme,
+	}
+	if err := r.reconcilePlan(context.TODO(), gateway, testPlan()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: gateway.Namespace, Name: PlanName(gateway)}
+	if err := r.Client.Get(context.TODO(), key, configMap); err != nil {
+		t.Fatalf("expected the plan to be published but got %v", err)
+	}
+	if !metav1.IsControlledBy(configMap, gateway) {
+		t.Errorf("expected the plan to be owned by the gateway")
+	}
+	gatewayPlan := GatewayPlan{}
+	if err := json.Unmarshal([]byte(configMap.Data[PlanKey]), &gatewayPlan); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if gatewayPlan.Generation != 2 || !reflect.DeepEqual(gatewayPlan.Add, []string{"c2"}) {
+		t.Errorf("unexpected plan %+v", gatewayPlan)
+	}
+
+	if err := r.deletePlan(context.TODO(), gateway); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := r.Client.Get(context.TODO(), key, configMap); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the plan to be deleted but got %v", err)
+	}
+	// deleting a missing plan is a no-op
+	if err := r.deletePlan(context.TODO(), gateway); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestReconcilePlanConflict(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   testutil.Namespace,
+			UID:         "test",
+			Annotations: map[string]string{PlanAnnotation: "true"},
+		},
+	}
+	userConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: PlanName(gateway), Namespace: testutil.Namespace},
+		Data:       map[string]string{"user": "data"},
+	}
+	scheme := testutil.GetValidTestScheme()
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(gateway, userConfigMap).Build(),
+		Scheme: scheme,
+	}
+	if err := r.reconcilePlan(context.TODO(), gateway, testPlan()); !errors.Is(err, errConfigMapConflict) {
+		t.Fatalf("expected a conflict but got %v", err)
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(context.TODO(), client.ObjectKeyFromObject(userConfigMap), configMap); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(configMap.Data, userConfigMap.Data) || metav1.IsControlledBy(configMap, gateway) {
+		t.Errorf("expected the ConfigMap of the user to be left untouched but got %v", configMap)
+	}
+	conflict, err := r.planConflicts(context.TODO(), gateway)
+	if err != nil || !conflict {
+		t.Errorf("expected the plan to conflict but got %v %v", conflict, err)
+	}
+	condition := buildPlanPublishedCondition(gateway, conflict)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != ConfigMapConflictReason {
+		t.Errorf("expected the conflict to be reported but got %v", condition)
+	}
+}
+
+func TestBuildPlanPublishedCondition(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 4}}
+	if condition := buildPlanPublishedCondition(gateway, false); condition != nil {
+		t.Errorf("expected no condition without the plan annotation but got %v", condition)
+	}
+	gateway.Annotations = map[string]string{PlanAnnotation: "true"}
+	condition := buildPlanPublishedCondition(gateway, false)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.ObservedGeneration != 4 {
+		t.Errorf("expected a true condition for the planned gateway but got %v", condition)
+	}
+	now := metav1.Now()
+	gateway.DeletionTimestamp = &now
+	if condition := buildPlanPublishedCondition(gateway, false); condition != nil {
+		t.Errorf("expected no condition for a deleted gateway but got %v", condition)
+	}
+}
@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// secretRefPermitted checks whether a ReferenceGrant in the namespace of the secret permits the gateway to reference it.
+// References within the namespace of the gateway are always permitted
+func secretRefPermitted(ctx context.Context, c client.Client, gateway *gatewayapiv1.Gateway, secretNamespace, secretName string) (bool, error) {
+	if secretNamespace == gateway.Namespace {
+		return true, nil
+	}
+	grants := &gatewayapiv1beta1.ReferenceGrantList{}
+	if err := c.List(ctx, grants, client.InNamespace(secretNamespace)); err != nil {
+		return false, err
+	}
+	for _, grant := range grants.Items {
+		if referenceGrantPermits(grant, gateway.Namespace, secretName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// referenceGrantPermits checks whether the grant permits gateways in the given namespace to reference the named secret
+func referenceGrantPermits(grant gatewayapiv1beta1.ReferenceGrant, gatewayNamespace, secretName string) bool {
+	from := false
+	for _, f := range grant.Spec.From {
+		if f.Group == gatewayapiv1.GroupName && f.Kind == "Gateway" && string(f.Namespace) == gatewayNamespace {
+			from = true
+			break
+		}
+	}
+	if !from {
+		return false
+	}
+	for _, to := range grant.Spec.To {
+		if to.Group != corev1.GroupName || to.Kind != "Secret" {
+			continue
+		}
+		if to.Name == nil || string(*to.Name) == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+// referenceGrantToGateways maps a ReferenceGrant to the gateways in the namespaces it grants access from
+// that reference a secret in the namespace of the grant
+func (r *GatewayReconciler) referenceGrantToGateways(ctx context.Context, o client.Object) []reconcile.Request {
+	log := crlog.FromContext(ctx)
+	grant, ok := o.(*gatewayapiv1beta1.ReferenceGrant)
+	if !ok {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, from := range grant.Spec.From {
+		if from.Group != gatewayapiv1.GroupName || from.Kind != "Gateway" {
+			continue
+		}
+		gateways := &gatewayapiv1.GatewayList{}
+		if err := r.Client.List(ctx, gateways, client.InNamespace(string(from.Namespace))); err != nil {
+			log.Error(err, "failed to list gateways for reference grant", "referencegrant", client.ObjectKeyFromObject(grant))
+			continue
+		}
+		for _, gateway := range gateways.Items {
+			if referencesSecretNamespace(gateway, grant.Namespace) {
+				log.V(3).Info("enqueuing gateway based on reference grant change", "gateway", gateway.Name, "namespace", gateway.Namespace)
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+			}
+		}
+	}
+	return requests
+}
+
+// referencesSecretNamespace checks whether any listener of the gateway references a secret in the given namespace
+func referencesSecretNamespace(gateway gatewayapiv1.Gateway, namespace string) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			if ref.Namespace != nil && string(*ref.Namespace) == namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+//go:build unit
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
+)
+
+func TestSecretRefPermitted(t *testing.T) {
+	secretNamespace := "certificates"
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testutil.DummyCRName,
+			Namespace: testutil.Namespace,
+		},
+	}
+	grant := func(fromNamespace string, name *gatewayapiv1.ObjectName) gatewayapiv1beta1.ReferenceGrant {
+		return gatewayapiv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "grant",
+				Namespace: secretNamespace,
+			},
+			Spec: gatewayapiv1beta1.ReferenceGrantSpec{
+				From: []gatewayapiv1beta1.ReferenceGrantFrom{
+					{
+						Group:     gatewayapiv1.GroupName,
+						Kind:      "Gateway",
+						Namespace: gatewayapiv1.Namespace(fromNamespace),
+					},
+				},
+				To: []gatewayapiv1beta1.ReferenceGrantTo{
+					{
+						Group: "",
+						Kind:  "Secret",
+						Name:  name,
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		grants          []gatewayapiv1beta1.ReferenceGrant
+		secretNamespace string
+		want            bool
+	}{
+		{
+			name:            "same namespace is always permitted",
+			secretNamespace: testutil.Namespace,
+			want:            true,
+		},
+		{
+			name:            "cross namespace without grant",
+			secretNamespace: secretNamespace,
+			want:            false,
+		},
+		{
+			name:            "cross namespace with grant for all secrets",
+			grants:          []gatewayapiv1beta1.ReferenceGrant{grant(testutil.Namespace, nil)},
+			secretNamespace: secretNamespace,
+			want:            true,
+		},
+		{
+			name:            "cross namespace with grant for the secret",
+			grants:          []gatewayapiv1beta1.ReferenceGrant{grant(testutil.Namespace, testutil.Pointer(gatewayapiv1.ObjectName(testutil.TLSSecretName)))},
+			secretNamespace: secretNamespace,
+			want:            true,
+		},
+		{
+			name:            "cross namespace with grant for another secret",
+			grants:          []gatewayapiv1beta1.ReferenceGrant{grant(testutil.Namespace, testutil.Pointer(gatewayapiv1.ObjectName("other")))},
+			secretNamespace: secretNamespace,
+			want:            false,
+		},
+		{
+			name:            "cross namespace with grant from another namespace",
+			grants:          []gatewayapiv1beta1.ReferenceGrant{grant("other", nil)},
+			secretNamespace: secretNamespace,
+			want:            false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := testutil.GetValidTestClient(&gatewayapiv1beta1.ReferenceGrantList{Items: testCase.grants})
+			got, err := secretRefPermitted(context.TODO(), c, gateway, testCase.secretNamespace, testutil.TLSSecretName)
+			if err != nil {
+				t.Fatalf("did not expect an error but got %s", err)
+			}
+			if got != testCase.want {
+				t.Errorf("secretRefPermitted() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
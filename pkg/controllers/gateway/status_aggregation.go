@@ -0,0 +1,117 @@
+package gateway
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// aggregateConditions aggregates the conditionType condition of the downstream gateways as described in
+// docs/proposals/status-aggregation.md. The aggregated status is True when the condition is True in every
+// cluster, False when it is False in any cluster and Unknown otherwise. The message is a semi-colon separated
+// list of the messages of the clusters where the condition is not True
+func aggregateConditions(conditionType string, clusterConditions map[string][]metav1.Condition) (metav1.ConditionStatus, string) {
+	clusters := make([]string, 0, len(clusterConditions))
+	for cluster := range clusterConditions {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	status := metav1.ConditionTrue
+	messages := []string{}
+	for _, cluster := range clusters {
+		condition := meta.FindStatusCondition(clusterConditions[cluster], conditionType)
+		if condition == nil {
+			if status == metav1.ConditionTrue {
+				status = metav1.ConditionUnknown
+			}
+			messages = append(messages, fmt.Sprintf("%s %s status unknown", cluster, conditionType))
+			continue
+		}
+		if condition.Status == metav1.ConditionTrue {
+			continue
+		}
+		if condition.Status == metav1.ConditionFalse {
+			status = metav1.ConditionFalse
+		} else if status == metav1.ConditionTrue {
+			status = metav1.ConditionUnknown
+		}
+		messages = append(messages, fmt.Sprintf("%s %s", cluster, condition.Message))
+	}
+	return status, strings.Join(messages, "; ")
+}
+
+// aggregateAcceptedCondition returns the accepted condition of the upstream gateway. The gateway is accepted by
+// this controller, so it is only considered not accepted once a downstream gateway reports it is not accepted
+func aggregateAcceptedCondition(generation int64, clusterConditions map[string][]metav1.Condition) metav1.Condition {
+	status, message := aggregateConditions(string(gatewayapiv1.GatewayConditionAccepted), clusterConditions)
+	if status != metav1.ConditionFalse {
+		return buildAcceptedCondition(generation, metav1.ConditionTrue)
+	}
+	return metav1.Condition{
+		Type:               string(gatewayapiv1.GatewayConditionAccepted),
+		Status:             metav1.ConditionFalse,
+		Reason:             string(gatewayapiv1.GatewayReasonInvalid),
+		Message:            message,
+		ObservedGeneration: generation,
+	}
+}
+
+// aggregateProgrammedCondition refines a programmed condition built from the placement result with the
+// programmed conditions reported by the downstream gateways
+func aggregateProgrammedCondition(placed metav1.Condition, clusterConditions map[string][]metav1.Condition) metav1.Condition {
+	if placed.Status != metav1.ConditionTrue {
+		return placed
+	}
+	status, message := aggregateConditions(string(gatewayapiv1.GatewayConditionProgrammed), clusterConditions)
+	switch status {
+	case metav1.ConditionFalse:
+		placed.Status = metav1.ConditionFalse
+		placed.Reason = string(gatewayapiv1.GatewayReasonInvalid)
+		placed.Message = message
+	case metav1.ConditionUnknown:
+		placed.Status = metav1.ConditionUnknown
+		placed.Reason = string(gatewayapiv1.GatewayReasonPending)
+		placed.Message = message
+	}
+	return placed
+}
+
+// buildListenerConditions returns the downstream listener conditions with the generation of the upstream gateway
+func buildListenerConditions(generation int64, downstream []metav1.Condition) []metav1.Condition {
+	conditions := []metav1.Condition{}
+	for _, condition := range downstream {
+		condition.ObservedGeneration = generation
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
+
+// buildListenersNotValidCondition updates the accepted condition to report the listeners that could not be placed.
+// The gateway is only considered not accepted when none of its listeners are valid
+func buildListenersNotValidCondition(generation int64, accepted metav1.Condition, listeners int, invalidListeners map[gatewayapiv1.SectionName]metav1.Condition) metav1.Condition {
+	names := make([]string, 0, len(invalidListeners))
+	for name := range invalidListeners {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	message := fmt.Sprintf("listeners %v are not valid", names)
+	if accepted.Status == metav1.ConditionFalse {
+		message = accepted.Message + "; " + message
+	}
+	status := accepted.Status
+	if len(invalidListeners) >= listeners {
+		status = metav1.ConditionFalse
+	}
+	return metav1.Condition{
+		Type:               string(gatewayapiv1.GatewayConditionAccepted),
+		Status:             status,
+		Reason:             string(gatewayapiv1.GatewayReasonListenersNotValid),
+		Message:            message,
+		ObservedGeneration: generation,
+	}
+}
@@ -0,0 +1,77 @@
+//go:build unit
+
+package gateway
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestAggregateConditions(t *testing.T) {
+	programmed := func(status metav1.ConditionStatus, message string) []metav1.Condition {
+		return []metav1.Condition{
+			{
+				Type:    string(gatewayapiv1.GatewayConditionProgrammed),
+				Status:  status,
+				Message: message,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name              string
+		clusterConditions map[string][]metav1.Condition
+		wantStatus        metav1.ConditionStatus
+		wantMessage       string
+	}{
+		{
+			name: "true in all clusters",
+			clusterConditions: map[string][]metav1.Condition{
+				"cluster-1": programmed(metav1.ConditionTrue, "programmed"),
+				"cluster-2": programmed(metav1.ConditionTrue, "programmed"),
+			},
+			wantStatus: metav1.ConditionTrue,
+		},
+		{
+			name: "false in some clusters",
+			clusterConditions: map[string][]metav1.Condition{
+				"cluster-3": programmed(metav1.ConditionFalse, "No listener configured for port 80"),
+				"cluster-2": programmed(metav1.ConditionTrue, "programmed"),
+				"cluster-1": programmed(metav1.ConditionFalse, "Listener certificate is expired"),
+			},
+			wantStatus:  metav1.ConditionFalse,
+			wantMessage: "cluster-1 Listener certificate is expired; cluster-3 No listener configured for port 80",
+		},
+		{
+			name: "missing in some clusters",
+			clusterConditions: map[string][]metav1.Condition{
+				"cluster-1": programmed(metav1.ConditionTrue, "programmed"),
+				"cluster-2": nil,
+			},
+			wantStatus:  metav1.ConditionUnknown,
+			wantMessage: "cluster-2 Programmed status unknown",
+		},
+		{
+			name: "false takes precedence over unknown",
+			clusterConditions: map[string][]metav1.Condition{
+				"cluster-1": nil,
+				"cluster-2": programmed(metav1.ConditionFalse, "address pending"),
+			},
+			wantStatus:  metav1.ConditionFalse,
+			wantMessage: "cluster-1 Programmed status unknown; cluster-2 address pending",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			status, message := aggregateConditions(string(gatewayapiv1.GatewayConditionProgrammed), testCase.clusterConditions)
+			if status != testCase.wantStatus {
+				t.Errorf("aggregateConditions() status = %v, want %v", status, testCase.wantStatus)
+			}
+			if message != testCase.wantMessage {
+				t.Errorf("aggregateConditions() message = %v, want %v", message, testCase.wantMessage)
+			}
+		})
+	}
+}
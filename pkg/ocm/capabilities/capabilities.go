@@ -0,0 +1,206 @@
+// Package capabilities describes what a spoke is able to run. The addon agent publishes the GatewayClasses and
+// whether they are accepted, the Gateway API version and the Kuadrant features of the spoke as ClusterClaims, which are reported in the status of
+// its ManagedCluster, so that the hub only places a gateway on the clusters able to run it.
+package capabilities
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/slice"
+)
+
+const (
+	// ReportedClaim is published with the Version of the capability claims by every spoke reporting its capabilities.
+	// A capability without a claim is empty when the spoke reports its capabilities, and unknown otherwise
+	ReportedClaim = "capabilities.kuadrant.io"
+	// GatewayClassesClaim lists the GatewayClasses of the spoke
+	GatewayClassesClaim = "gatewayclasses.capabilities.kuadrant.io"
+	// AcceptedGatewayClassesClaim lists the GatewayClasses of the spoke accepted by their controller
+	AcceptedGatewayClassesClaim = "acceptedgatewayclasses.capabilities.kuadrant.io"
+	// GatewayAPIVersionClaim holds the bundle version of the Gateway API CRDs installed on the spoke
+	GatewayAPIVersionClaim = "gatewayapiversion.capabilities.kuadrant.io"
+	// FeaturesClaim lists the Kuadrant and Gateway API CRDs installed on the spoke
+	FeaturesClaim = "features.capabilities.kuadrant.io"
+
+	// Version is the version of the format of the capability claims
+	Version = "v1"
+
+	// GatewayAPIBundleVersionAnnotation is set on the Gateway API CRDs to the version of the release they are part of
+	GatewayAPIBundleVersionAnnotation = "gateway.networking.k8s.io/bundle-version"
+
+	// maxClaimValueLength is the longest value of a ClusterClaim. Longer lists are split across claims
+	maxClaimValueLength = 1024
+	separator           = ","
+)
+
+// Capabilities of a spoke
+type Capabilities struct {
+	// GatewayClasses are the names of the GatewayClasses of the spoke
+	GatewayClasses []string
+	// AcceptedGatewayClasses are the names of the GatewayClasses of the spoke with an Accepted=True condition
+	AcceptedGatewayClasses []string
+	// GatewayAPIVersion is the bundle version of the Gateway API CRDs, empty when the Gateway API is not installed
+	GatewayAPIVersion string
+	// Features are the names of the Kuadrant and Gateway API CRDs installed on the spoke, for example
+	// authpolicies.kuadrant.io
+	Features []string
+}
+
+// Requirements of a gateway placed on a spoke
+type Requirements struct {
+	// GatewayClass is the class of the downstream gateway
+	GatewayClass string
+	// Features that must be installed on the spoke
+	Features []string
+	// MinGatewayAPIVersion is the oldest Gateway API version the gateway can be placed on, ignored when empty
+	MinGatewayAPIVersion string
+}
+
+// ClusterClaims returns the value of the ClusterClaim of each capability. A ClusterClaim can't be empty so the
+// empty capabilities are left out. A list longer than a ClusterClaim value is split between the claim of the
+// capability and continuation claims named after it, see PartClaimName
+func (c *Capabilities) ClusterClaims() map[string]string {
+	claims := map[string]string{ReportedClaim: Version}
+	values := map[string][]string{
+		GatewayClassesClaim:         c.GatewayClasses,
+		AcceptedGatewayClassesClaim: c.AcceptedGatewayClasses,
+		GatewayAPIVersionClaim:      {c.GatewayAPIVersion},
+		FeaturesClaim:               c.Features,
+	}
+	for name, value := range values {
+		for part, partValue := range chunk(value) {
+			claims[PartClaimName(name, part)] = partValue
+		}
+	}
+	return claims
+}
+
+// PartClaimName returns the name of the claim holding the given part of the value of a capability. The first part is
+// held by the claim of the capability, the next ones by claims whose first label is suffixed with the part number,
+// for example gatewayclasses-2.capabilities.kuadrant.io
+func PartClaimName(name string, part int) string {
+	if part == 0 {
+		return name
+	}
+	label, domain, _ := strings.Cut(name, ".")
+	return fmt.Sprintf("%s-%d.%s", label, part+1, domain)
+}
+
+// IsClaim returns whether the ClusterClaim is one of the claims the capabilities are published in
+func IsClaim(name string) bool {
+	return name == ReportedClaim || strings.HasSuffix(name, "."+ReportedClaim)
+}
+
+// FromClusterClaims returns the capabilities published in the cluster claims of a ManagedCluster, or nil when the
+// cluster doesn't report its capabilities
+func FromClusterClaims(claims []clusterv1.ManagedClusterClaim) *Capabilities {
+	values := map[string]string{}
+	for _, claim := range claims {
+		values[claim.Name] = claim.Value
+	}
+	if _, reported := values[ReportedClaim]; !reported {
+		return nil
+	}
+	return &Capabilities{
+		GatewayClasses:         split(joinParts(values, GatewayClassesClaim)),
+		AcceptedGatewayClasses: split(joinParts(values, AcceptedGatewayClassesClaim)),
+		GatewayAPIVersion:      joinParts(values, GatewayAPIVersionClaim),
+		Features:               split(joinParts(values, FeaturesClaim)),
+	}
+}
+
+// Missing returns a description of each of the requirements the capabilities don't satisfy
+func (c *Capabilities) Missing(requirements Requirements) []string {
+	missing := []string{}
+	if requirements.GatewayClass != "" && !slice.ContainsString(c.GatewayClasses, requirements.GatewayClass) {
+		missing = append(missing, fmt.Sprintf("gateway class %s", requirements.GatewayClass))
+	}
+	for _, feature := range requirements.Features {
+		if !slice.ContainsString(c.Features, feature) {
+			missing = append(missing, fmt.Sprintf("feature %s", feature))
+		}
+	}
+	if requirements.MinGatewayAPIVersion != "" {
+		if !atLeast(c.GatewayAPIVersion, requirements.MinGatewayAPIVersion) {
+			found := c.GatewayAPIVersion
+			if found == "" {
+				found = "none"
+			}
+			missing = append(missing, fmt.Sprintf("Gateway API %s or later (found %s)", requirements.MinGatewayAPIVersion, found))
+		}
+	}
+	return missing
+}
+
+// atLeast returns whether the version is the same as or later than the minimum. An unparsable version is never
+// recent enough
+func atLeast(current, minimum string) bool {
+	currentVersion, err := version.ParseGeneric(current)
+	if err != nil {
+		return false
+	}
+	minimumVersion, err := version.ParseGeneric(minimum)
+	if err != nil {
+		return false
+	}
+	return currentVersion.AtLeast(minimumVersion)
+}
+
+// ValidateVersion returns an error when the Gateway API version can't be compared
+func ValidateVersion(gatewayAPIVersion string) error {
+	_, err := version.ParseGeneric(gatewayAPIVersion)
+	return err
+}
+
+// chunk returns the sorted values joined into as few claim values as possible. Empty values, and values too long
+// for a claim, which Kubernetes names never are, are left out
+func chunk(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	chunks := []string{}
+	current := ""
+	for _, value := range sorted {
+		if value == "" || len(value) > maxClaimValueLength {
+			continue
+		}
+		if current != "" && len(current)+len(separator)+len(value) > maxClaimValueLength {
+			chunks = append(chunks, current)
+			current = ""
+		}
+		if current != "" {
+			current += separator
+		}
+		current += value
+	}
+	if current != "" {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// joinParts returns the value of the capability published in the claim of the given name and its continuation
+// claims
+func joinParts(values map[string]string, name string) string {
+	parts := []string{}
+	for part := 0; ; part++ {
+		value, ok := values[PartClaimName(name, part)]
+		if !ok {
+			break
+		}
+		parts = append(parts, value)
+	}
+	return strings.Join(parts, separator)
+}
+
+func split(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, separator)
+}
@@ -0,0 +1,167 @@
+//go:build unit
+
+package capabilities
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestClusterClaims(t *testing.T) {
+	cases := []struct {
+		name         string
+		capabilities *Capabilities
+		expected     map[string]string
+	}{
+		{
+			name: "all capabilities",
+			capabilities: &Capabilities{
+				GatewayClasses:         []string{"istio", "eg"},
+				AcceptedGatewayClasses: []string{"istio"},
+				GatewayAPIVersion:      "v1.0.0",
+				Features:               []string{"ratelimitpolicies.kuadrant.io", "authpolicies.kuadrant.io"},
+			},
+			expected: map[string]string{
+				ReportedClaim:               Version,
+				GatewayClassesClaim:         "eg,istio",
+				AcceptedGatewayClassesClaim: "istio",
+				GatewayAPIVersionClaim:      "v1.0.0",
+				FeaturesClaim:               "authpolicies.kuadrant.io,ratelimitpolicies.kuadrant.io",
+			},
+		},
+		{
+			name:         "no capabilities",
+			capabilities: &Capabilities{},
+			expected:     map[string]string{ReportedClaim: Version},
+		},
+		{
+			name: "list split across claims",
+			capabilities: &Capabilities{
+				GatewayClasses: []string{strings.Repeat("c", 253), strings.Repeat("b", 253), strings.Repeat("a", 253), strings.Repeat("e", 253), strings.Repeat("d", 253)},
+			},
+			expected: map[string]string{
+				ReportedClaim:       Version,
+				GatewayClassesClaim: strings.Join([]string{strings.Repeat("a", 253), strings.Repeat("b", 253), strings.Repeat("c", 253), strings.Repeat("d", 253)}, ","),
+				"gatewayclasses-2.capabilities.kuadrant.io": strings.Repeat("e", 253),
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			claims := testCase.capabilities.ClusterClaims()
+			if !reflect.DeepEqual(claims, testCase.expected) {
+				t.Errorf("expected claims %v but got %v", testCase.expected, claims)
+			}
+		})
+	}
+}
+
+func TestFromClusterClaims(t *testing.T) {
+	if capabilities := FromClusterClaims([]clusterv1.ManagedClusterClaim{{Name: GatewayClassesClaim, Value: "istio"}}); capabilities != nil {
+		t.Errorf("expected no capabilities without the %s claim but got %v", ReportedClaim, capabilities)
+	}
+
+	published := &Capabilities{
+		GatewayClasses:         []string{"eg", "istio"},
+		AcceptedGatewayClasses: []string{"istio"},
+		GatewayAPIVersion:      "v1.0.0",
+		Features:               []string{"authpolicies.kuadrant.io"},
+	}
+	claims := []clusterv1.ManagedClusterClaim{{Name: "id.k8s.io", Value: "c1"}}
+	for name, value := range published.ClusterClaims() {
+		claims = append(claims, clusterv1.ManagedClusterClaim{Name: name, Value: value})
+	}
+	if capabilities := FromClusterClaims(claims); !reflect.DeepEqual(capabilities, published) {
+		t.Errorf("expected capabilities %v but got %v", published, capabilities)
+	}
+
+	features := []string{}
+	for i := 0; i < 100; i++ {
+		features = append(features, fmt.Sprintf("feature%03d.kuadrant.io", i))
+	}
+	split := &Capabilities{Features: features}
+	splitClaims := []clusterv1.ManagedClusterClaim{}
+	for name, value := range split.ClusterClaims() {
+		if len(value) > maxClaimValueLength {
+			t.Errorf("expected claim %s to fit in a claim value but got %d characters", name, len(value))
+		}
+		splitClaims = append(splitClaims, clusterv1.ManagedClusterClaim{Name: name, Value: value})
+	}
+	if len(splitClaims) < 4 {
+		t.Errorf("expected the features to be split across claims but got %v", splitClaims)
+	}
+	if capabilities := FromClusterClaims(splitClaims); !reflect.DeepEqual(capabilities, split) {
+		t.Errorf("expected capabilities %v but got %v", split, capabilities)
+	}
+
+	if capabilities := FromClusterClaims([]clusterv1.ManagedClusterClaim{{Name: ReportedClaim, Value: Version}}); capabilities == nil || len(capabilities.GatewayClasses) != 0 {
+		t.Errorf("expected empty capabilities but got %v", capabilities)
+	}
+}
+
+func TestIsClaim(t *testing.T) {
+	for name, expected := range map[string]bool{
+		ReportedClaim:                          true,
+		GatewayClassesClaim:                    true,
+		PartClaimName(FeaturesClaim, 2):        true,
+		"id.k8s.io":                            false,
+		"product.open-cluster-management.io":   false,
+		"capabilities.kuadrant.io.example.com": false,
+	} {
+		if IsClaim(name) != expected {
+			t.Errorf("expected IsClaim(%s) to be %v", name, expected)
+		}
+	}
+}
+
+func TestMissing(t *testing.T) {
+	capabilities := &Capabilities{
+		GatewayClasses:    []string{"istio"},
+		GatewayAPIVersion: "v1.0.0",
+		Features:          []string{"authpolicies.kuadrant.io"},
+	}
+	cases := []struct {
+		name         string
+		capabilities *Capabilities
+		requirements Requirements
+		expected     []string
+	}{
+		{
+			name:         "satisfied",
+			capabilities: capabilities,
+			requirements: Requirements{GatewayClass: "istio", Features: []string{"authpolicies.kuadrant.io"}, MinGatewayAPIVersion: "v0.8.0"},
+			expected:     []string{},
+		},
+		{
+			name:         "missing gateway class and feature",
+			capabilities: capabilities,
+			requirements: Requirements{GatewayClass: "eg", Features: []string{"authpolicies.kuadrant.io", "ratelimitpolicies.kuadrant.io"}},
+			expected:     []string{"gateway class eg", "feature ratelimitpolicies.kuadrant.io"},
+		},
+		{
+			name:         "gateway api too old",
+			capabilities: capabilities,
+			requirements: Requirements{MinGatewayAPIVersion: "1.1"},
+			expected:     []string{"Gateway API 1.1 or later (found v1.0.0)"},
+		},
+		{
+			name:         "gateway api not installed",
+			capabilities: &Capabilities{},
+			requirements: Requirements{MinGatewayAPIVersion: "v1.0.0"},
+			expected:     []string{"Gateway API v1.0.0 or later (found none)"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if missing := testCase.capabilities.Missing(testCase.requirements); !reflect.DeepEqual(missing, testCase.expected) {
+				t.Errorf("expected missing %v but got %v", testCase.expected, missing)
+			}
+		})
+	}
+}
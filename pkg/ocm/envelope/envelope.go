@@ -0,0 +1,246 @@
+// Package envelope implements the encryption of secrets placed on spoke clusters. Each spoke generates an
+// X25519 key pair and publishes the public key as a ClusterClaim. Secrets are encrypted on the hub with a key
+// derived from an ephemeral X25519 exchange with that public key so that only the spoke can decrypt them.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SecretType is the type of the secrets holding an encrypted payload
+	SecretType corev1.SecretType = "kuadrant.io/encrypted"
+	// PayloadKey is the data key of the encrypted payload
+	PayloadKey = "payload"
+	// EncryptedSuffix is appended to the name of the secret to name its encrypted counterpart
+	EncryptedSuffix = "-encrypted"
+	// DecryptedNameAnnotation holds the name of the secret to create from the encrypted payload
+	DecryptedNameAnnotation = "kuadrant.io/decrypted-name"
+	// DecryptedTypeAnnotation holds the type of the secret to create from the encrypted payload
+	DecryptedTypeAnnotation = "kuadrant.io/decrypted-type"
+	// PayloadHashAnnotation identifies the plaintext and recipient of the payload so that unchanged secrets keep their
+	// ciphertext. It is an HMAC keyed with a secret only the hub holds so that it can't be used to guess the plaintext
+	PayloadHashAnnotation = "kuadrant.io/payload-hash"
+	// PublicKeyClaim is the name of the ClusterClaim holding the base64 encoded public key of the spoke
+	PublicKeyClaim = "publickey.encryption.kuadrant.io"
+
+	keySize   = 32
+	kdfDomain = "kuadrant.io/envelope"
+)
+
+// GenerateKey returns a new X25519 private key
+func GenerateKey() (*ecdh.PrivateKey, error) {
+	return ecdh.X25519().GenerateKey(rand.Reader)
+}
+
+// EncodePublicKey returns the public key in the format published in the ClusterClaim
+func EncodePublicKey(key *ecdh.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(key.Bytes())
+}
+
+// ClusterPublicKey returns the public key published by the cluster in the ClusterClaim
+func ClusterPublicKey(cluster *clusterv1.ManagedCluster) (*ecdh.PublicKey, error) {
+	for _, claim := range cluster.Status.ClusterClaims {
+		if claim.Name != PublicKeyClaim {
+			continue
+		}
+		key, err := DecodePublicKey(claim.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key published by cluster %s : %w", cluster.Name, err)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("cluster %s has not published an encryption key in the %s cluster claim", cluster.Name, PublicKeyClaim)
+}
+
+// GenerateHashKey returns a new key for PayloadHash
+func GenerateHashKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// DecodePublicKey parses a public key published in the ClusterClaim
+func DecodePublicKey(value string) (*ecdh.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return ecdh.X25519().NewPublicKey(raw)
+}
+
+// Encrypt encrypts the plaintext for the holder of the private key matching the public key.
+// The additional data is authenticated but not encrypted and must be passed unchanged to Decrypt
+func Encrypt(publicKey *ecdh.PublicKey, plaintext, additionalData []byte) ([]byte, error) {
+	ephemeral, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	shared, err := ephemeral.ECDH(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(shared, ephemeral.PublicKey().Bytes(), publicKey.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := append(ephemeral.PublicKey().Bytes(), nonce...)
+	return aead.Seal(ciphertext, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt
+func Decrypt(privateKey *ecdh.PrivateKey, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < keySize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(ciphertext[:keySize])
+	if err != nil {
+		return nil, err
+	}
+	shared, err := privateKey.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(shared, ephemeral.Bytes(), privateKey.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < keySize+aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce := ciphertext[keySize : keySize+aead.NonceSize()]
+	return aead.Open(nil, nonce, ciphertext[keySize+aead.NonceSize():], additionalData)
+}
+
+func newAEAD(shared, ephemeralPublicKey, recipientPublicKey []byte) (cipher.AEAD, error) {
+	kdf := sha256.New()
+	kdf.Write([]byte(kdfDomain))
+	kdf.Write(shared)
+	kdf.Write(ephemeralPublicKey)
+	kdf.Write(recipientPublicKey)
+	block, err := aes.NewCipher(kdf.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptedName returns the name of the encrypted counterpart of the secret
+func EncryptedName(name string) string {
+	return name + EncryptedSuffix
+}
+
+// PayloadHash identifies the data of the secret and the recipient key with an HMAC keyed with the hash key
+func PayloadHash(secret *corev1.Secret, publicKey *ecdh.PublicKey, hashKey []byte) string {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := hmac.New(sha256.New, hashKey)
+	hash.Write(publicKey.Bytes())
+	hash.Write([]byte(secret.Type))
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write(secret.Data[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// EncryptSecret returns an encrypted counterpart of the secret that only the holder of the private key
+// matching the public key can turn back into the secret. The payload hash is keyed with the hash key
+func EncryptSecret(secret *corev1.Secret, publicKey *ecdh.PublicKey, hashKey []byte) (*corev1.Secret, error) {
+	plaintext, err := json.Marshal(secret.Data)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := Encrypt(publicKey, plaintext, additionalData(secret.Namespace, secret.Name))
+	if err != nil {
+		return nil, err
+	}
+	annotations := map[string]string{}
+	for key, value := range secret.Annotations {
+		annotations[key] = value
+	}
+	annotations[DecryptedNameAnnotation] = secret.Name
+	annotations[DecryptedTypeAnnotation] = string(secret.Type)
+	annotations[PayloadHashAnnotation] = PayloadHash(secret, publicKey, hashKey)
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        EncryptedName(secret.Name),
+			Namespace:   secret.Namespace,
+			Labels:      secret.Labels,
+			Annotations: annotations,
+		},
+		Type: SecretType,
+		Data: map[string][]byte{
+			PayloadKey: payload,
+		},
+	}, nil
+}
+
+// DecryptSecret returns the secret encrypted by EncryptSecret
+func DecryptSecret(encrypted *corev1.Secret, privateKey *ecdh.PrivateKey) (*corev1.Secret, error) {
+	if encrypted.Type != SecretType {
+		return nil, fmt.Errorf("expected secret type %s but got %s", SecretType, encrypted.Type)
+	}
+	name, ok := encrypted.Annotations[DecryptedNameAnnotation]
+	if !ok {
+		return nil, fmt.Errorf("missing %s annotation", DecryptedNameAnnotation)
+	}
+	plaintext, err := Decrypt(privateKey, encrypted.Data[PayloadKey], additionalData(encrypted.Namespace, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret %s/%s : %w", encrypted.Namespace, encrypted.Name, err)
+	}
+	data := map[string][]byte{}
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, err
+	}
+	annotations := map[string]string{}
+	for key, value := range encrypted.Annotations {
+		if key == DecryptedNameAnnotation || key == DecryptedTypeAnnotation || key == PayloadHashAnnotation {
+			continue
+		}
+		annotations[key] = value
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   encrypted.Namespace,
+			Labels:      encrypted.Labels,
+			Annotations: annotations,
+		},
+		Type: corev1.SecretType(encrypted.Annotations[DecryptedTypeAnnotation]),
+		Data: data,
+	}, nil
+}
+
+// additionalData binds the payload to the secret it decrypts to so it cannot be replayed under another name
+func additionalData(namespace, name string) []byte {
+	return []byte(namespace + "/" + name)
+}
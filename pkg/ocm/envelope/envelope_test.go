@@ -0,0 +1,184 @@
+//go:build unit
+
+package envelope
+
+import (
+	"reflect"
+	"testing"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEncryptDecryptSecret(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tls",
+			Namespace:   "kuadrant-test",
+			Labels:      map[string]string{"gateway": "test"},
+			Annotations: map[string]string{"cert-manager.io/certificate-name": "tls"},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	}
+
+	hashKey, err := GenerateHashKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := EncryptSecret(secret, key.PublicKey(), hashKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encrypted.Name != "tls-encrypted" || encrypted.Type != SecretType {
+		t.Fatalf("unexpected encrypted secret %s of type %s", encrypted.Name, encrypted.Type)
+	}
+	if _, ok := encrypted.Data[corev1.TLSPrivateKeyKey]; ok {
+		t.Fatalf("expected the secret data to be encrypted")
+	}
+
+	testCases := []struct {
+		name        string
+		encrypted   func() *corev1.Secret
+		useOtherKey bool
+		wantErr     bool
+	}{
+		{
+			name:      "decrypts with the recipient key",
+			encrypted: func() *corev1.Secret { return encrypted.DeepCopy() },
+		},
+		{
+			name:        "fails with another key",
+			encrypted:   func() *corev1.Secret { return encrypted.DeepCopy() },
+			useOtherKey: true,
+			wantErr:     true,
+		},
+		{
+			name: "fails when renamed",
+			encrypted: func() *corev1.Secret {
+				renamed := encrypted.DeepCopy()
+				renamed.Annotations[DecryptedNameAnnotation] = "other"
+				return renamed
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails when tampered",
+			encrypted: func() *corev1.Secret {
+				tampered := encrypted.DeepCopy()
+				tampered.Data[PayloadKey][len(tampered.Data[PayloadKey])-1] ^= 1
+				return tampered
+			},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			privateKey := key
+			if testCase.useOtherKey {
+				privateKey = otherKey
+			}
+			decrypted, err := DecryptSecret(testCase.encrypted(), privateKey)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("DecryptSecret() error = %v, wantErr %v", err, testCase.wantErr)
+			}
+			if testCase.wantErr {
+				return
+			}
+			if decrypted.Name != secret.Name || decrypted.Type != secret.Type ||
+				!reflect.DeepEqual(decrypted.Data, secret.Data) ||
+				!reflect.DeepEqual(decrypted.Labels, secret.Labels) ||
+				!reflect.DeepEqual(decrypted.Annotations, secret.Annotations) {
+				t.Errorf("DecryptSecret() = %v, want %v", decrypted, secret)
+			}
+		})
+	}
+}
+
+func TestPayloadHash(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashKey, err := GenerateHashKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherHashKey, err := GenerateHashKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	changed := secret.DeepCopy()
+	changed.Data[corev1.TLSPrivateKeyKey] = []byte("other")
+
+	hash := PayloadHash(secret, key.PublicKey(), hashKey)
+	if hash != PayloadHash(secret.DeepCopy(), key.PublicKey(), hashKey) {
+		t.Errorf("expected the hash of an unchanged secret to be stable")
+	}
+	if hash == PayloadHash(changed, key.PublicKey(), hashKey) {
+		t.Errorf("expected the hash to change with the data of the secret")
+	}
+	// without the hash key the hash can't be recomputed from a guessed plaintext
+	if hash == PayloadHash(secret, key.PublicKey(), otherHashKey) {
+		t.Errorf("expected the hash to depend on the hash key")
+	}
+}
+
+func TestClusterPublicKey(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name    string
+		claims  []clusterv1.ManagedClusterClaim
+		wantErr bool
+	}{
+		{
+			name:   "published key",
+			claims: []clusterv1.ManagedClusterClaim{{Name: PublicKeyClaim, Value: EncodePublicKey(key.PublicKey())}},
+		},
+		{
+			name:    "missing key",
+			claims:  []clusterv1.ManagedClusterClaim{{Name: "other", Value: "value"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid key",
+			claims:  []clusterv1.ManagedClusterClaim{{Name: PublicKeyClaim, Value: "invalid"}},
+			wantErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status:     clusterv1.ManagedClusterStatus{ClusterClaims: testCase.claims},
+			}
+			publicKey, err := ClusterPublicKey(cluster)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("ClusterPublicKey() error = %v, wantErr %v", err, testCase.wantErr)
+			}
+			if !testCase.wantErr && !publicKey.Equal(key.PublicKey()) {
+				t.Errorf("expected the published key")
+			}
+		})
+	}
+}
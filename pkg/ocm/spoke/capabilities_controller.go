@@ -0,0 +1,159 @@
+package spoke
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/capabilities"
+)
+
+const (
+	// DefaultCapabilitiesResyncPeriod is how often the capabilities are discovered when nothing they depend on changes
+	DefaultCapabilitiesResyncPeriod = 5 * time.Minute
+
+	gatewaysCRDName = "gateways." + gatewayapiv1.GroupName
+)
+
+// featureGroupSuffixes are the API groups whose CRDs are reported as features
+var featureGroupSuffixes = []string{".kuadrant.io", "." + gatewayapiv1.GroupName}
+
+// capabilitiesRequest is the single request the capabilities are reconciled under, as every change is published in
+// the same claims
+var capabilitiesRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: capabilities.ReportedClaim}}
+
+// CapabilitiesReconciler publishes the GatewayClasses and whether they are accepted, the Gateway API version and the Kuadrant features of the spoke
+// as ClusterClaims
+type CapabilitiesReconciler struct {
+	client.Client
+	// ResyncPeriod is how often the capabilities are discovered when nothing they depend on changes. Defaults to
+	// DefaultCapabilitiesResyncPeriod
+	ResyncPeriod time.Duration
+}
+
+func (r *CapabilitiesReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	discovered, err := r.discover(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	claims := discovered.ClusterClaims()
+	for _, name := range sets.List(sets.KeySet(claims)) {
+		claim := &clusterv1alpha1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, claim, func() error {
+			claim.Spec.Value = claims[name]
+			return nil
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	// remove the claims of the capabilities that are now empty or need fewer parts
+	existing := &clusterv1alpha1.ClusterClaimList{}
+	if err := r.Client.List(ctx, existing); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range existing.Items {
+		claim := &existing.Items[i]
+		if _, published := claims[claim.Name]; published || !capabilities.IsClaim(claim.Name) {
+			continue
+		}
+		if err := r.Client.Delete(ctx, claim); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	log.V(3).Info("published capabilities", "claims", claims)
+
+	resyncPeriod := r.ResyncPeriod
+	if resyncPeriod == 0 {
+		resyncPeriod = DefaultCapabilitiesResyncPeriod
+	}
+	return ctrl.Result{RequeueAfter: resyncPeriod}, nil
+}
+
+// discover returns the capabilities of the spoke
+func (r *CapabilitiesReconciler) discover(ctx context.Context) (*capabilities.Capabilities, error) {
+	discovered := &capabilities.Capabilities{}
+
+	gatewayClasses := &gatewayapiv1.GatewayClassList{}
+	// the Gateway API may not be installed
+	if err := r.Client.List(ctx, gatewayClasses); err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	for _, gatewayClass := range gatewayClasses.Items {
+		discovered.GatewayClasses = append(discovered.GatewayClasses, gatewayClass.Name)
+		if meta.IsStatusConditionTrue(gatewayClass.Status.Conditions, string(gatewayapiv1.GatewayClassConditionStatusAccepted)) {
+			discovered.AcceptedGatewayClasses = append(discovered.AcceptedGatewayClasses, gatewayClass.Name)
+		}
+	}
+
+	crds := &metav1.PartialObjectMetadataList{}
+	crds.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinitionList"))
+	if err := r.Client.List(ctx, crds); err != nil {
+		return nil, err
+	}
+	for _, crd := range crds.Items {
+		if crd.Name == gatewaysCRDName {
+			discovered.GatewayAPIVersion = crd.Annotations[capabilities.GatewayAPIBundleVersionAnnotation]
+		}
+		if isFeature(crd.Name) {
+			discovered.Features = append(discovered.Features, crd.Name)
+		}
+	}
+	sort.Strings(discovered.GatewayClasses)
+	sort.Strings(discovered.AcceptedGatewayClasses)
+	sort.Strings(discovered.Features)
+	return discovered, nil
+}
+
+func isFeature(crdName string) bool {
+	for _, suffix := range featureGroupSuffixes {
+		if strings.HasSuffix(crdName, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CapabilitiesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	enqueue := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{capabilitiesRequest}
+	})
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("capabilities").
+		// only the metadata of the CRDs is needed, their schemas are not cached
+		Watches(&apiextensionsv1.CustomResourceDefinition{}, enqueue, builder.OnlyMetadata).
+		// restore the claims if they are changed on the spoke
+		Watches(&clusterv1alpha1.ClusterClaim{}, enqueue, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return capabilities.IsClaim(object.GetName())
+		})))
+	// the GatewayClasses can only be watched when the Gateway API is installed before the agent starts, they are
+	// otherwise discovered on resync
+	if _, err := mgr.GetRESTMapper().RESTMapping(gatewayapiv1.SchemeGroupVersion.WithKind("GatewayClass").GroupKind(), gatewayapiv1.SchemeGroupVersion.Version); err == nil {
+		b = b.Watches(&gatewayapiv1.GatewayClass{}, enqueue)
+	}
+	return b.Complete(r)
+}
@@ -0,0 +1,103 @@
+//go:build unit
+
+package spoke
+
+import (
+	"context"
+	"testing"
+
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/capabilities"
+)
+
+func TestCapabilitiesReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clusterv1alpha1.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
+	_ = gatewayapiv1.AddToScheme(scheme)
+
+	crd := func(name string, annotations map[string]string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&gatewayapiv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "istio"},
+				Status: gatewayapiv1.GatewayClassStatus{Conditions: []metav1.Condition{
+					{Type: string(gatewayapiv1.GatewayClassConditionStatusAccepted), Status: metav1.ConditionTrue},
+				}},
+			},
+			&gatewayapiv1.GatewayClass{ObjectMeta: metav1.ObjectMeta{Name: "eg"}},
+			crd("gateways.gateway.networking.k8s.io", map[string]string{capabilities.GatewayAPIBundleVersionAnnotation: "v1.0.0"}),
+			crd("authpolicies.kuadrant.io", nil),
+			crd("certificates.cert-manager.io", nil),
+			// published by another component
+			&clusterv1alpha1.ClusterClaim{ObjectMeta: metav1.ObjectMeta{Name: "unused"}},
+			// left by a longer list of features
+			&clusterv1alpha1.ClusterClaim{ObjectMeta: metav1.ObjectMeta{Name: capabilities.PartClaimName(capabilities.FeaturesClaim, 1)}},
+		).
+		Build()
+	r := &CapabilitiesReconciler{Client: c}
+
+	if _, err := r.Reconcile(context.TODO(), ctrl.Request{}); err != nil {
+		t.Fatalf("did not expect an error but got %v", err)
+	}
+	expected := map[string]string{
+		capabilities.ReportedClaim:               capabilities.Version,
+		capabilities.GatewayClassesClaim:         "eg,istio",
+		capabilities.AcceptedGatewayClassesClaim: "istio",
+		capabilities.GatewayAPIVersionClaim:      "v1.0.0",
+		capabilities.FeaturesClaim:               "authpolicies.kuadrant.io,gateways.gateway.networking.k8s.io",
+	}
+	for name, value := range expected {
+		claim := &clusterv1alpha1.ClusterClaim{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Name: name}, claim); err != nil {
+			t.Fatalf("expected claim %s but got %v", name, err)
+		}
+		if claim.Spec.Value != value {
+			t.Errorf("expected claim %s to be %q but got %q", name, value, claim.Spec.Value)
+		}
+	}
+
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: capabilities.PartClaimName(capabilities.FeaturesClaim, 1)}, &clusterv1alpha1.ClusterClaim{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the unused part of the features claim to be removed but got %v", err)
+	}
+
+	// the Gateway API is removed
+	for _, gatewayClass := range []string{"istio", "eg"} {
+		if err := c.Delete(context.TODO(), &gatewayapiv1.GatewayClass{ObjectMeta: metav1.ObjectMeta{Name: gatewayClass}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Delete(context.TODO(), crd("gateways.gateway.networking.k8s.io", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(context.TODO(), ctrl.Request{}); err != nil {
+		t.Fatalf("did not expect an error but got %v", err)
+	}
+	for _, name := range []string{capabilities.GatewayClassesClaim, capabilities.AcceptedGatewayClassesClaim, capabilities.GatewayAPIVersionClaim} {
+		if err := c.Get(context.TODO(), client.ObjectKey{Name: name}, &clusterv1alpha1.ClusterClaim{}); !k8serrors.IsNotFound(err) {
+			t.Errorf("expected claim %s to be removed but got %v", name, err)
+		}
+	}
+	claim := &clusterv1alpha1.ClusterClaim{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: capabilities.FeaturesClaim}, claim); err != nil || claim.Spec.Value != "authpolicies.kuadrant.io" {
+		t.Errorf("expected the features claim to be updated but got %v %v", claim.Spec.Value, err)
+	}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: "unused"}, &clusterv1alpha1.ClusterClaim{}); err != nil {
+		t.Errorf("expected the claims of other components to be left alone but got %v", err)
+	}
+}
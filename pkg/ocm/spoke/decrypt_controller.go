@@ -0,0 +1,76 @@
+package spoke
+
+import (
+	"context"
+	"crypto/ecdh"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/envelope"
+)
+
+// SecretDecryptionReconciler turns the encrypted secrets placed by the hub back into the secrets they were encrypted from.
+// The decrypted secret is owned by the encrypted secret so it is removed with it
+type SecretDecryptionReconciler struct {
+	client.Client
+	Scheme     *runtime.Scheme
+	PrivateKey *ecdh.PrivateKey
+}
+
+func (r *SecretDecryptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	encrypted := &corev1.Secret{}
+	if err := r.Client.Get(ctx, req.NamespacedName, encrypted); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if encrypted.Type != envelope.SecretType || encrypted.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	decrypted, err := envelope.DecryptSecret(encrypted, r.PrivateKey)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      decrypted.Name,
+			Namespace: decrypted.Namespace,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.CreationTimestamp.IsZero() {
+			secret.Type = decrypted.Type
+		}
+		secret.Labels = decrypted.Labels
+		secret.Annotations = decrypted.Annotations
+		secret.Data = decrypted.Data
+		return controllerutil.SetControllerReference(encrypted, secret, r.Scheme)
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	log.V(3).Info("reconciled decrypted secret", "secret", client.ObjectKeyFromObject(secret), "result", result)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SecretDecryptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Secret{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			secret, ok := object.(*corev1.Secret)
+			return ok && secret.Type == envelope.SecretType
+		}))).
+		// restore the decrypted secret if it is changed on the spoke
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
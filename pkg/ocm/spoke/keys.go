@@ -0,0 +1,73 @@
+package spoke
+
+import (
+	"context"
+	"crypto/ecdh"
+	"fmt"
+
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/envelope"
+)
+
+const (
+	// KeySecretName is the name of the secret holding the private key of the spoke
+	KeySecretName = "kuadrant-addon-encryption-key"
+	// PrivateKeyKey is the data key of the private key in the key secret
+	PrivateKeyKey = "private.key"
+)
+
+// EnsureEncryptionKey returns the private key of the spoke, generating and storing it in the given namespace on first use
+func EnsureEncryptionKey(ctx context.Context, c client.Client, namespace string) (*ecdh.PrivateKey, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: KeySecretName}, secret)
+	if err == nil {
+		return ecdh.X25519().NewPrivateKey(secret.Data[PrivateKeyKey])
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	key, err := envelope.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      KeySecretName,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			PrivateKeyKey: key.Bytes(),
+		},
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			// another replica stored its key first
+			return EnsureEncryptionKey(ctx, c, namespace)
+		}
+		return nil, fmt.Errorf("failed to store encryption key : %w", err)
+	}
+	return key, nil
+}
+
+// PublishPublicKey publishes the public key of the spoke in a ClusterClaim so that it is reported to the hub
+func PublishPublicKey(ctx context.Context, c client.Client, key *ecdh.PrivateKey) error {
+	claim := &clusterv1alpha1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: envelope.PublicKeyClaim,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, claim, func() error {
+		claim.Spec.Value = envelope.EncodePublicKey(key.PublicKey())
+		return nil
+	})
+	return err
+}
@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 	testutil "github.com/Kuadrant/multicluster-gateway-controller/test/util"
 )
 
@@ -19,7 +20,7 @@ func NewTestGatewayPlacer() *FakeGatewayPlacer {
 	return &FakeGatewayPlacer{}
 }
 
-func (p *FakeGatewayPlacer) Place(_ context.Context, upstream *gatewayapiv1.Gateway, _ *gatewayapiv1.Gateway, _ ...metav1.Object) (sets.Set[string], error) {
+func (p *FakeGatewayPlacer) Place(_ context.Context, upstream *gatewayapiv1.Gateway, _ *gatewayapiv1.Gateway, _ placement.ClusterCustomiser, _ ...metav1.Object) (sets.Set[string], error) {
 	if upstream.Labels == nil {
 		return nil, nil
 	}
@@ -60,3 +61,39 @@ func (p *FakeGatewayPlacer) GetAddresses(_ context.Context, _ *gatewayapiv1.Gate
 		},
 	}, nil
 }
+
+func (p *FakeGatewayPlacer) GetConditions(_ context.Context, _ *gatewayapiv1.Gateway, _ string) ([]metav1.Condition, error) {
+	return []metav1.Condition{
+		{
+			Type:   string(gatewayapiv1.GatewayConditionAccepted),
+			Status: metav1.ConditionTrue,
+			Reason: string(gatewayapiv1.GatewayReasonAccepted),
+		},
+		{
+			Type:   string(gatewayapiv1.GatewayConditionProgrammed),
+			Status: metav1.ConditionTrue,
+			Reason: string(gatewayapiv1.GatewayReasonProgrammed),
+		},
+	}, nil
+}
+
+func (p *FakeGatewayPlacer) ListenerConditions(_ context.Context, _ *gatewayapiv1.Gateway, _ string, _ string) ([]metav1.Condition, error) {
+	return []metav1.Condition{}, nil
+}
+
+func (p *FakeGatewayPlacer) GetSecretAnnotations(_ context.Context, _ *gatewayapiv1.Gateway, _ string) (map[string]map[string]string, error) {
+	return map[string]map[string]string{}, nil
+}
+
+func (p *FakeGatewayPlacer) Plan(_ context.Context, upstream *gatewayapiv1.Gateway, _ *gatewayapiv1.Gateway, _ placement.ClusterCustomiser, _ ...metav1.Object) (*placement.Plan, error) {
+	if upstream.Labels == nil {
+		return &placement.Plan{Clusters: []placement.ClusterPlan{}}, nil
+	}
+	return &placement.Plan{
+		Clusters: []placement.ClusterPlan{{Cluster: testutil.Cluster, Action: placement.PlanUnchanged}},
+	}, nil
+}
+
+func (p *FakeGatewayPlacer) Remove(_ context.Context, _ *gatewayapiv1.Gateway) (sets.Set[string], error) {
+	return sets.New[string](), nil
+}
@@ -0,0 +1,165 @@
+package placement
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
+)
+
+const (
+	clusterLabel = "cluster"
+
+	// ClusterLeaseName is the lease the klusterlet of a managed cluster renews in the namespace of the cluster on the
+	// hub. The work agent reports the status feedback of the ManifestWorks alongside it
+	ClusterLeaseName = "managed-cluster-lease"
+
+	// collectTimeout bounds the reads from the cache made while the metrics are scraped
+	collectTimeout = 5 * time.Second
+)
+
+var (
+	manifestWorkApplyFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mgc_manifestwork_apply_failures_total",
+			Help: "Number of failures creating or updating the ManifestWork of a gateway in the namespace of a cluster, or applying it on the cluster",
+		},
+		[]string{clusterLabel},
+	)
+
+	clusterGatewaysDesc = prometheus.NewDesc(
+		"mgc_cluster_gateways",
+		"Number of gateways placed and applied on the cluster",
+		[]string{clusterLabel}, nil,
+	)
+	clusterPendingRemovalsDesc = prometheus.NewDesc(
+		"mgc_cluster_pending_gateway_removals",
+		"Number of gateways waiting for their grace period to expire before being removed from the cluster",
+		[]string{clusterLabel}, nil,
+	)
+	clusterFeedbackStalenessDesc = prometheus.NewDesc(
+		"mgc_cluster_feedback_staleness_seconds",
+		"Seconds since the klusterlet of the cluster last renewed its lease, the status feedback of the gateways placed on the cluster is not refreshed while the lease is not renewed",
+		[]string{clusterLabel}, nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(manifestWorkApplyFailures)
+}
+
+// ClusterCollector reports the state of the gateways placed on each cluster. The state is read from the cache when
+// the metrics are scraped so that it can not drift from the ManifestWorks and ManagedClusters it is computed from.
+// The cluster leases are read through the API reader so that the leases of the hub are not cached
+type ClusterCollector struct {
+	c         client.Client
+	apiReader client.Reader
+}
+
+var _ prometheus.Collector = &ClusterCollector{}
+
+func NewClusterCollector(c client.Client, apiReader client.Reader) *ClusterCollector {
+	return &ClusterCollector{c: c, apiReader: apiReader}
+}
+
+func (cc *ClusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clusterGatewaysDesc
+	ch <- clusterPendingRemovalsDesc
+	ch <- clusterFeedbackStalenessDesc
+}
+
+func (cc *ClusterCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+	logger := log.Log.WithName("placement-metrics")
+
+	clusters := &clusterv1.ManagedClusterList{}
+	if err := cc.c.List(ctx, clusters); err != nil {
+		logger.Error(err, "failed to list managed clusters")
+		return
+	}
+	placed := map[string]int{}
+	pending := map[string]int{}
+	for _, cluster := range clusters.Items {
+		placed[cluster.Name] = 0
+		pending[cluster.Name] = 0
+	}
+
+	works := &workv1.ManifestWorkList{}
+	if err := cc.c.List(ctx, works, client.HasLabels{WorkManifestLabel}); err != nil {
+		logger.Error(err, "failed to list gateway manifest works")
+	} else {
+		for _, work := range works.Items {
+			if _, ok := placed[work.Namespace]; !ok || work.DeletionTimestamp != nil {
+				continue
+			}
+			if metadata.HasAnnotation(&work, gracePeriod.GraceTimestampAnnotation) {
+				pending[work.Namespace]++
+			}
+			if meta.IsStatusConditionTrue(work.Status.Conditions, string(workv1.ManifestApplied)) {
+				placed[work.Namespace]++
+			}
+		}
+		for cluster := range placed {
+			ch <- prometheus.MustNewConstMetric(clusterGatewaysDesc, prometheus.GaugeValue, float64(placed[cluster]), cluster)
+			ch <- prometheus.MustNewConstMetric(clusterPendingRemovalsDesc, prometheus.GaugeValue, float64(pending[cluster]), cluster)
+		}
+	}
+
+	for _, cluster := range clusters.Items {
+		lease := &coordinationv1.Lease{}
+		if err := cc.apiReader.Get(ctx, client.ObjectKey{Namespace: cluster.Name, Name: ClusterLeaseName}, lease); err != nil {
+			logger.V(3).Info("failed to get cluster lease", "cluster", cluster.Name, "error", err)
+			continue
+		}
+		if lease.Spec.RenewTime == nil {
+			continue
+		}
+		staleness := time.Since(lease.Spec.RenewTime.Time).Seconds()
+		ch <- prometheus.MustNewConstMetric(clusterFeedbackStalenessDesc, prometheus.GaugeValue, staleness, cluster.Name)
+	}
+}
+
+// recordApplyFailure counts a failure to create or update the ManifestWork of a gateway on the cluster
+func recordApplyFailure(cluster string) {
+	manifestWorkApplyFailures.WithLabelValues(cluster).Inc()
+}
+
+// ApplyFailureHandler counts the ManifestWorks of gateways the work agent of the cluster reports as failing to apply.
+// A work is counted when its Applied condition turns False, so that a failure is counted once rather than on each
+// status update of the work
+func ApplyFailureHandler() handler.EventHandler {
+	return handler.Funcs{
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+			oldWork, ok := e.ObjectOld.(*workv1.ManifestWork)
+			if !ok {
+				return
+			}
+			newWork, ok := e.ObjectNew.(*workv1.ManifestWork)
+			if !ok || !metadata.HasLabel(newWork, WorkManifestLabel) {
+				return
+			}
+			if applyFailed(newWork) && !applyFailed(oldWork) {
+				recordApplyFailure(newWork.Namespace)
+			}
+		},
+	}
+}
+
+func applyFailed(work *workv1.ManifestWork) bool {
+	return meta.IsStatusConditionFalse(work.Status.Conditions, string(workv1.ManifestApplied))
+}
@@ -0,0 +1,178 @@
+//go:build unit
+
+package placement_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
+)
+
+func TestClusterCollector(t *testing.T) {
+	applied := []metav1.Condition{{Type: string(workv1.ManifestApplied), Status: metav1.ConditionTrue}}
+	work := func(cluster, name string, annotations map[string]string, conditions []metav1.Condition) *workv1.ManifestWork {
+		return &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   cluster,
+				Labels:      map[string]string{placement.WorkManifestLabel: name},
+				Annotations: annotations,
+			},
+			Status: workv1.ManifestWorkStatus{Conditions: conditions},
+		}
+	}
+	renewed := metav1.NewMicroTime(time.Now().Add(-time.Hour))
+	objects := []client.Object{
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c1"}},
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "c2"}},
+		work("c1", "gw1", nil, applied),
+		work("c1", "gw2", map[string]string{gracePeriod.GraceTimestampAnnotation: "1"}, applied),
+		work("c2", "gw1", nil, nil),
+		// works in namespaces that are not clusters are ignored
+		work("unknown", "gw1", nil, applied),
+		&coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: placement.ClusterLeaseName, Namespace: "c1"},
+			Spec:       coordinationv1.LeaseSpec{RenewTime: &renewed},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(objects...).Build()
+	collector := placement.NewClusterCollector(c, c)
+
+	expected := `
+# HELP mgc_cluster_gateways Number of gateways placed and applied on the cluster
+# TYPE mgc_cluster_gateways gauge
+mgc_cluster_gateways{cluster="c1"} 2
+mgc_cluster_gateways{cluster="c2"} 0
+# HELP mgc_cluster_pending_gateway_removals Number of gateways waiting for their grace period to expire before being removed from the cluster
+# TYPE mgc_cluster_pending_gateway_removals gauge
+mgc_cluster_pending_gateway_removals{cluster="c1"} 1
+mgc_cluster_pending_gateway_removals{cluster="c2"} 0
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "mgc_cluster_gateways", "mgc_cluster_pending_gateway_removals"); err != nil {
+		t.Errorf("unexpected cluster metrics: %v", err)
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "mgc_cluster_feedback_staleness_seconds" {
+			continue
+		}
+		// only the cluster with a lease reports its staleness
+		if len(family.GetMetric()) != 1 {
+			t.Fatalf("expected the staleness of a single cluster but got %v", family.GetMetric())
+		}
+		if staleness := family.GetMetric()[0].GetGauge().GetValue(); staleness < time.Hour.Seconds() {
+			t.Errorf("expected the staleness to be at least an hour but got %v", staleness)
+		}
+		return
+	}
+	t.Errorf("expected the feedback staleness to be reported")
+}
+
+func TestApplyFailureHandler(t *testing.T) {
+	work := func(labels map[string]string, status metav1.ConditionStatus) *workv1.ManifestWork {
+		mw := &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "apply-failures", Labels: labels}}
+		if status != "" {
+			mw.Status.Conditions = []metav1.Condition{{Type: string(workv1.ManifestApplied), Status: status}}
+		}
+		return mw
+	}
+	gatewayWork := map[string]string{placement.WorkManifestLabel: "gw"}
+
+	testCases := []struct {
+		name     string
+		old      *workv1.ManifestWork
+		new      *workv1.ManifestWork
+		expected float64
+	}{
+		{
+			name:     "work turns not applied",
+			old:      work(gatewayWork, metav1.ConditionTrue),
+			new:      work(gatewayWork, metav1.ConditionFalse),
+			expected: 1,
+		},
+		{
+			name:     "new work fails to apply",
+			old:      work(gatewayWork, ""),
+			new:      work(gatewayWork, metav1.ConditionFalse),
+			expected: 1,
+		},
+		{
+			name:     "work still not applied",
+			old:      work(gatewayWork, metav1.ConditionFalse),
+			new:      work(gatewayWork, metav1.ConditionFalse),
+			expected: 0,
+		},
+		{
+			name:     "work applied",
+			old:      work(gatewayWork, metav1.ConditionFalse),
+			new:      work(gatewayWork, metav1.ConditionTrue),
+			expected: 0,
+		},
+		{
+			name:     "work of another controller",
+			old:      work(nil, metav1.ConditionTrue),
+			new:      work(nil, metav1.ConditionFalse),
+			expected: 0,
+		},
+	}
+	h := placement.ApplyFailureHandler()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			counter, err := applyFailures()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			h.Update(context.TODO(), event.UpdateEvent{ObjectOld: testCase.old, ObjectNew: testCase.new}, nil)
+			updated, err := applyFailures()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if updated-counter != testCase.expected {
+				t.Errorf("expected %v apply failures to be counted but got %v", testCase.expected, updated-counter)
+			}
+		})
+	}
+}
+
+// applyFailures gathers the apply failures counted on the cluster of the test works
+func applyFailures() (float64, error) {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		return 0, err
+	}
+	for _, family := range families {
+		if family.GetName() != "mgc_manifestwork_apply_failures_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "cluster" && label.GetValue() == "apply-failures" {
+					return metric.GetCounter().GetValue(), nil
+				}
+			}
+		}
+	}
+	return 0, nil
+}
@@ -2,7 +2,9 @@ package placement
 
 import (
 	"context"
+	"crypto/ecdh"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -21,11 +23,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/metadata"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/envelope"
 )
 
 const (
@@ -33,47 +38,151 @@ const (
 	rbacName          = "open-cluster-management:klusterlet-work:gateway"
 	rbacManifest      = "gateway-rbac"
 	WorkManifestLabel = "kuadrant.io/manifestKey"
+
+	// UnmanagedNamespaceAnnotation marks a downstream gateway placed in a namespace that is not owned by the
+	// controller. The namespace is created if missing but is never updated, and is orphaned when the gateway is removed
+	UnmanagedNamespaceAnnotation = "kuadrant.io/unmanaged-namespace"
+	// ResyncAnnotation holds a token set on the upstream gateway to force its ManifestWorks to be re-applied. The objects
+	// placed on each cluster are annotated with the token, so a new token changes every ManifestWork of the gateway and
+	// the work agents re-apply their manifests
+	ResyncAnnotation = "kuadrant.io/resync"
+
+	// ClusterAddedReason is the reason of the event recorded when the gateway is placed on a cluster
+	ClusterAddedReason = "ClusterAdded"
+	// ClusterRemovalScheduledReason is the reason of the event recorded when the removal of the gateway from a
+	// cluster starts its grace period
+	ClusterRemovalScheduledReason = "ClusterRemovalScheduled"
+	// GracePeriodExpiredReason is the reason of the event recorded when the grace period of a removal expires
+	GracePeriodExpiredReason = "GracePeriodExpired"
+	// ClusterRemovedReason is the reason of the event recorded when the gateway is removed from a cluster
+	ClusterRemovedReason = "ClusterRemoved"
 )
 
+// ClusterCustomiser applies the cluster specific changes to the copy of the downstream gateway placed on the cluster.
+// A customiser returning an error wrapping ErrClusterRefused leaves the gateway off the cluster
+type ClusterCustomiser func(ctx context.Context, cluster string, downstream *gatewayapiv1.Gateway) error
+
+// ErrClusterRefused is wrapped by the errors of a ClusterCustomiser refusing to place the gateway on a cluster
+var ErrClusterRefused = errors.New("cluster refused")
+
 type ocmPlacer struct {
-	c client.Client
+	c              client.Client
+	encryptSecrets bool
+	hashKey        []byte
+	recorder       record.EventRecorder
 }
 
-func NewOCMPlacer(c client.Client) *ocmPlacer {
+// OCMPlacerOption configures optional behaviour of the OCM placer
+type OCMPlacerOption func(*ocmPlacer)
+
+// WithSecretEncryption encrypts the secrets placed on each cluster with the public key published by the
+// kuadrant addon agent of the cluster, so that ManifestWorks only hold ciphertext. The hash key keys the payload
+// hashes telling whether a secret changed, and must not be published
+func WithSecretEncryption(hashKey []byte) OCMPlacerOption {
+	return func(op *ocmPlacer) {
+		op.encryptSecrets = true
+		op.hashKey = hashKey
+	}
+}
 
-	return &ocmPlacer{
+// WithEventRecorder records the placement of the gateway on each cluster and its removal as events on the upstream
+// gateway
+func WithEventRecorder(recorder record.EventRecorder) OCMPlacerOption {
+	return func(op *ocmPlacer) {
+		op.recorder = recorder
+	}
+}
+
+func NewOCMPlacer(c client.Client, opts ...OCMPlacerOption) *ocmPlacer {
+
+	op := &ocmPlacer{
 		c: c,
 	}
+	for _, opt := range opts {
+		opt(op)
+	}
+	return op
 }
 
 func (op *ocmPlacer) GetAddresses(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) ([]gatewayapiv1.GatewayAddress, error) {
-	workname := WorkName(gateway)
 	addresses := []gatewayapiv1.GatewayAddress{}
-	rootMeta, _ := k8smeta.Accessor(gateway)
-	mw := &workv1.ManifestWork{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      workname,
-			Namespace: downstream,
-		},
-	}
-	var err error
-	if err = op.c.Get(ctx, client.ObjectKeyFromObject(mw), mw, &client.GetOptions{}); err != nil {
+	values, err := op.getGatewayFeedback(ctx, gateway, downstream)
+	if err != nil {
 		return addresses, err
 	}
-	for _, m := range mw.Status.ResourceStatus.Manifests {
-		if m.ResourceMeta.Group == gateway.GetObjectKind().GroupVersionKind().Group && m.ResourceMeta.Name == rootMeta.GetName() {
-			for _, value := range m.StatusFeedbacks.Values {
-				if value.Name == "addresses" {
-					err = json.Unmarshal([]byte(*value.Value.JsonRaw), &addresses)
-					break
-				}
-			}
+	for _, value := range values {
+		if value.Name == "addresses" {
+			err = json.Unmarshal([]byte(*value.Value.JsonRaw), &addresses)
+			break
 		}
 	}
 	return addresses, err
 }
 
 func (op *ocmPlacer) ListenerTotalAttachedRoutes(ctx context.Context, gateway *gatewayapiv1.Gateway, listenerName string, downstream string) (int, error) {
+	values, err := op.getGatewayFeedback(ctx, gateway, downstream)
+	if err != nil {
+		return 0, err
+	}
+	attachedRoutesStatusKey := strings.ToLower(fmt.Sprintf("listener%sAttachedRoutes", listenerName))
+	for _, value := range values {
+		if strings.ToLower(value.Name) == attachedRoutesStatusKey {
+			return int(*value.Value.Integer), nil
+		}
+	}
+	return 0, fmt.Errorf("no listener %s status found", listenerName)
+
+}
+
+func (op *ocmPlacer) GetConditions(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) ([]metav1.Condition, error) {
+	values, err := op.getGatewayFeedback(ctx, gateway, downstream)
+	if err != nil {
+		return nil, err
+	}
+	return conditionsFromFeedback(values, "conditions")
+}
+
+func (op *ocmPlacer) ListenerConditions(ctx context.Context, gateway *gatewayapiv1.Gateway, listenerName string, downstream string) ([]metav1.Condition, error) {
+	values, err := op.getGatewayFeedback(ctx, gateway, downstream)
+	if err != nil {
+		return nil, err
+	}
+	return conditionsFromFeedback(values, fmt.Sprintf("listener%sConditions", listenerName))
+}
+
+// GetSecretAnnotations returns the annotations of the secrets placed with the gateway as applied in the given
+// cluster, keyed by the name of the secret in the downstream gateway namespace
+func (op *ocmPlacer) GetSecretAnnotations(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) (map[string]map[string]string, error) {
+	mw := &workv1.ManifestWork{}
+	if err := op.c.Get(ctx, client.ObjectKey{Namespace: downstream, Name: WorkName(gateway)}, mw); err != nil {
+		return nil, err
+	}
+	secretAnnotations := map[string]map[string]string{}
+	for _, m := range mw.Status.ResourceStatus.Manifests {
+		if m.ResourceMeta.Group != "" || m.ResourceMeta.Kind != "Secret" {
+			continue
+		}
+		for _, value := range m.StatusFeedbacks.Values {
+			if value.Name != "annotations" || value.Value.JsonRaw == nil {
+				continue
+			}
+			annotations := map[string]string{}
+			if err := json.Unmarshal([]byte(*value.Value.JsonRaw), &annotations); err != nil {
+				return nil, fmt.Errorf("failed to decode annotations of secret %s : %w", m.ResourceMeta.Name, err)
+			}
+			name := m.ResourceMeta.Name
+			// encrypted secrets are reported under the name of the secret they decrypt to
+			if decryptedName, ok := annotations[envelope.DecryptedNameAnnotation]; ok {
+				name = decryptedName
+			}
+			secretAnnotations[name] = annotations
+		}
+	}
+	return secretAnnotations, nil
+}
+
+// getGatewayFeedback returns the status feedback values of the downstream gateway in the given cluster
+func (op *ocmPlacer) getGatewayFeedback(ctx context.Context, gateway *gatewayapiv1.Gateway, downstream string) ([]workv1.FeedbackValue, error) {
 	workname := WorkName(gateway)
 	rootMeta, _ := k8smeta.Accessor(gateway)
 	mw := &workv1.ManifestWork{
@@ -83,20 +192,32 @@ func (op *ocmPlacer) ListenerTotalAttachedRoutes(ctx context.Context, gateway *g
 		},
 	}
 	if err := op.c.Get(ctx, client.ObjectKeyFromObject(mw), mw, &client.GetOptions{}); err != nil {
-		return 0, err
+		return nil, err
 	}
 	for _, m := range mw.Status.ResourceStatus.Manifests {
 		if m.ResourceMeta.Group == gateway.GetObjectKind().GroupVersionKind().Group && m.ResourceMeta.Name == rootMeta.GetName() {
-			for _, value := range m.StatusFeedbacks.Values {
-				attachedRoutesStatusKey := strings.ToLower(fmt.Sprintf("listener%sAttachedRoutes", listenerName))
-				if strings.ToLower(value.Name) == attachedRoutesStatusKey {
-					return int(*value.Value.Integer), nil
-				}
-			}
+			return m.StatusFeedbacks.Values, nil
 		}
 	}
-	return 0, fmt.Errorf("no listener %s status found", listenerName)
+	return nil, nil
+}
 
+// conditionsFromFeedback decodes the raw json conditions fed back under the given name
+func conditionsFromFeedback(values []workv1.FeedbackValue, name string) ([]metav1.Condition, error) {
+	for _, value := range values {
+		if !strings.EqualFold(value.Name, name) {
+			continue
+		}
+		if value.Value.JsonRaw == nil {
+			return nil, fmt.Errorf("feedback %s is not a json value", name)
+		}
+		conditions := []metav1.Condition{}
+		if err := json.Unmarshal([]byte(*value.Value.JsonRaw), &conditions); err != nil {
+			return nil, fmt.Errorf("failed to decode feedback %s : %w", name, err)
+		}
+		return conditions, nil
+	}
+	return nil, fmt.Errorf("no %s status found", name)
 }
 
 func WorkName(rootObj runtime.Object) string {
@@ -105,8 +226,9 @@ func WorkName(rootObj runtime.Object) string {
 	return strings.ToLower(fmt.Sprintf("%s-%s-%s", kind, rootMeta.GetNamespace(), rootMeta.GetName()))
 }
 
-// Place ensures the gateway is placed onto the chosen clusters by creating the required manifestwork resources
-func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Gateway, downStreamGateway *gatewayapiv1.Gateway, children ...metav1.Object) (sets.Set[string], error) {
+// Place ensures the gateway is placed onto the chosen clusters by creating the required manifestwork resources.
+// When set, customise is applied to a copy of the downstream gateway for each cluster
+func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Gateway, downStreamGateway *gatewayapiv1.Gateway, customise ClusterCustomiser, children ...metav1.Object) (sets.Set[string], error) {
 	//PoC currently each object is put into its own manifestwork. This shouldn't be needed but would require finding the manifest work and replacing the existing object
 	log := log.Log
 	log.V(3).Info("placement: placing ", "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
@@ -123,38 +245,32 @@ func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Ga
 		return emyptySet, err
 	}
 
-	// not in target clusters so need to be removed
-	removeFrom := existingClusters.Difference(placementTargets)
-	log.V(3).Info("placement: ", "removeFrom", removeFrom.UnsortedList(), "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
 	// if being deleted entirely remove manifest from all existing clusters
 	if upStreamGateway.GetDeletionTimestamp() != nil {
-		log.V(3).Info("placement: ", "deleting gateway from ", existingClusters.UnsortedList(), "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
-		for _, cluster := range existingClusters.UnsortedList() {
-			// being deleted need to remove from clusters
-			w := &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{
-				Name:      workname,
-				Namespace: cluster,
-			}}
-			if err := op.c.Delete(ctx, w, &client.DeleteOptions{}); client.IgnoreNotFound(err) != nil {
-				return existingClusters, err
-			}
-			existingClusters.Delete(cluster)
-		}
-		return existingClusters, nil
+		return op.Remove(ctx, upStreamGateway)
 	}
-	objects := []metav1.Object{downStreamGateway}
-	objects = append(objects, children...)
-	for _, cluster := range placementTargets.UnsortedList() {
+	changes, err := op.clusterChanges(ctx, workname, upStreamGateway, downStreamGateway, customise, placementTargets, existingClusters, children)
+	if err != nil {
+		return existingClusters, err
+	}
+	removeFrom := changes.removeFrom
+	for cluster, clusterGateway := range changes.place {
+		objects := []metav1.Object{clusterGateway}
+		objects = append(objects, children...)
 		log.V(3).Info("placement: ", "adding gateway rbac to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
 		if err := op.defaultRBAC(ctx, cluster); err != nil {
 			log.V(3).Info("placement: ", "adding gateway rbac to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace, "error", err)
 			return existingClusters, err
 		}
 		log.V(3).Info("placement: ", "adding gateway to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
-		if err := op.createUpdateClusterManifests(ctx, workname, upStreamGateway, downStreamGateway, cluster, objects...); err != nil {
+		created, err := op.createUpdateClusterManifests(ctx, workname, upStreamGateway, clusterGateway, cluster, objects...)
+		if err != nil {
 			log.V(3).Info("placement: ", "adding gateway to cluster ", cluster, "gateway", upStreamGateway.Name, "error", err)
 			return existingClusters, err
 		}
+		if created {
+			op.eventf(upStreamGateway, v1.EventTypeNormal, ClusterAddedReason, "gateway placed on cluster %s", cluster)
+		}
 		log.V(3).Info("placement: ", "added gateway to cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
 		existingClusters.Insert(cluster)
 	}
@@ -189,11 +305,23 @@ func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Ga
 			log.V(3).Info(fmt.Sprintf("ManagedCluster not found '%s', ignoring grace period", cluster))
 			ignoreGrace = true
 		}
+		graceScheduled := metadata.HasAnnotation(w, gracePeriod.GraceTimestampAnnotation)
 		if err := gracePeriod.GracefulDelete(ctx, op.c, w, ignoreGrace); err != nil {
+			if errors.Is(err, gracePeriod.ErrGracePeriodNotExpired) && !graceScheduled {
+				op.eventf(upStreamGateway, v1.EventTypeNormal, ClusterRemovalScheduledReason, "gateway removal from cluster %s scheduled after a grace period of %s", cluster, gracePeriod.DefaultGracePeriod)
+			}
 			// use a multi-error
 			log.V(3).Info("error during graceful delete", "error", err)
 			return existingClusters, err
 		}
+		if graceScheduled && !ignoreGrace {
+			op.eventf(upStreamGateway, v1.EventTypeNormal, GracePeriodExpiredReason, "grace period of the gateway removal from cluster %s expired", cluster)
+		}
+		if ignoreGrace {
+			op.eventf(upStreamGateway, v1.EventTypeNormal, ClusterRemovedReason, "gateway removed from cluster %s without a grace period as the ManagedCluster was not found", cluster)
+		} else {
+			op.eventf(upStreamGateway, v1.EventTypeNormal, ClusterRemovedReason, "gateway removed from cluster %s", cluster)
+		}
 
 		log.V(3).Info("graceful delete of gateway manifestwork complete, deleting RBAC")
 		rbac := &workv1.ManifestWork{
@@ -212,6 +340,91 @@ func (op *ocmPlacer) Place(ctx context.Context, upStreamGateway *gatewayapiv1.Ga
 	return existingClusters, nil
 }
 
+// Remove removes the gateway and its objects from every cluster it has a ManifestWork in, without a grace period. It
+// returns the clusters the gateway could not be removed from
+func (op *ocmPlacer) Remove(ctx context.Context, upStreamGateway *gatewayapiv1.Gateway) (sets.Set[string], error) {
+	existing := &workv1.ManifestWorkList{}
+	if err := op.c.List(ctx, existing, client.MatchingLabels{WorkManifestLabel: WorkName(upStreamGateway)}); err != nil {
+		return sets.New[string](), err
+	}
+	remaining := sets.New[string]()
+	for _, work := range existing.Items {
+		remaining.Insert(work.Namespace)
+	}
+	log.Log.V(3).Info("placement: ", "deleting gateway from ", remaining.UnsortedList(), "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
+	for _, work := range existing.Items {
+		if err := op.c.Delete(ctx, &work, &client.DeleteOptions{}); client.IgnoreNotFound(err) != nil {
+			return remaining, err
+		}
+		op.eventf(upStreamGateway, v1.EventTypeNormal, ClusterRemovedReason, "gateway removed from cluster %s", work.Namespace)
+		remaining.Delete(work.Namespace)
+	}
+	return remaining, nil
+}
+
+// clusterChanges are the changes placing a gateway makes to the clusters
+type clusterChanges struct {
+	// place holds the customised gateway placed on each targeted cluster
+	place map[string]*gatewayapiv1.Gateway
+	// refused holds the reason each refused cluster gave
+	refused map[string]string
+	// removeFrom are the clusters the gateway is removed from
+	removeFrom sets.Set[string]
+}
+
+// clusterChanges customises the gateway for each targeted cluster and works out the clusters it is removed from
+func (op *ocmPlacer) clusterChanges(ctx context.Context, workname string, upStreamGateway *gatewayapiv1.Gateway, downStreamGateway *gatewayapiv1.Gateway, customise ClusterCustomiser, placementTargets, existingClusters sets.Set[string], children []metav1.Object) (*clusterChanges, error) {
+	log := log.Log
+	changes := &clusterChanges{
+		place:   map[string]*gatewayapiv1.Gateway{},
+		refused: map[string]string{},
+		// not in target clusters so need to be removed
+		removeFrom: existingClusters.Difference(placementTargets),
+	}
+	refuse := func(cluster string, err error) error {
+		log.V(3).Info("placement: ", "refused cluster ", cluster, "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace, "reason", err.Error())
+		changes.refused[cluster] = err.Error()
+		// the gateway is removed from a cluster that no longer accepts it
+		getErr := op.c.Get(ctx, client.ObjectKey{Namespace: cluster, Name: workname}, &workv1.ManifestWork{})
+		if client.IgnoreNotFound(getErr) != nil {
+			return getErr
+		}
+		if getErr == nil {
+			changes.removeFrom.Insert(cluster)
+		}
+		return nil
+	}
+	for _, cluster := range placementTargets.UnsortedList() {
+		clusterGateway := downStreamGateway
+		if customise != nil {
+			clusterGateway = downStreamGateway.DeepCopy()
+			if err := customise(ctx, cluster, clusterGateway); err != nil {
+				if errors.Is(err, ErrClusterRefused) {
+					if err := refuse(cluster, err); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				return nil, fmt.Errorf("failed to customise gateway for cluster %s: %w", cluster, err)
+			}
+		}
+		if op.encryptSecrets && hasSecrets(children) {
+			if _, err := op.clusterPublicKey(ctx, cluster); err != nil {
+				if !errors.Is(err, ErrClusterRefused) {
+					return nil, err
+				}
+				if err := refuse(cluster, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		changes.place[cluster] = clusterGateway
+	}
+	log.V(3).Info("placement: ", "removeFrom", changes.removeFrom.UnsortedList(), "gateway", upStreamGateway.Name, "gateway ns", upStreamGateway.Namespace)
+	return changes, nil
+}
+
 // GetPlacedClusters will return the list of clusters this gateway has been successfully placed on
 func (op *ocmPlacer) GetPlacedClusters(ctx context.Context, gateway *gatewayapiv1.Gateway) (sets.Set[string], error) {
 	existing := &workv1.ManifestWorkList{}
@@ -265,12 +478,24 @@ func (op *ocmPlacer) GetClusters(ctx context.Context, gateway *gatewayapiv1.Gate
 	return targetClusters, nil
 }
 
-func (op *ocmPlacer) createUpdateClusterManifests(ctx context.Context, manifestName string, upstream *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, cluster string, obj ...metav1.Object) error {
+// createUpdateClusterManifests places the downstream gateway and its objects on the cluster, returning whether the
+// ManifestWork was created
+func (op *ocmPlacer) createUpdateClusterManifests(ctx context.Context, manifestName string, upstream *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, cluster string, obj ...metav1.Object) (bool, error) {
+	work, err := op.clusterWork(ctx, manifestName, upstream, downstream, cluster, obj...)
+	if err != nil {
+		return false, err
+	}
+	log.Log.V(3).Info("placement: creating updating maniftests for ", "cluster", cluster)
+	return op.createUpdateManifest(ctx, cluster, *work)
+}
+
+// clusterWork renders the ManifestWork placing the downstream gateway and its objects on the cluster
+func (op *ocmPlacer) clusterWork(ctx context.Context, manifestName string, upstream *gatewayapiv1.Gateway, downstream *gatewayapiv1.Gateway, cluster string, obj ...metav1.Object) (*workv1.ManifestWork, error) {
 	log := log.Log
 	// set up gateway manifest
 	key, err := cache.MetaNamespaceKeyFunc(upstream)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	work := workv1.ManifestWork{
 		ObjectMeta: metav1.ObjectMeta{
@@ -282,9 +507,19 @@ func (op *ocmPlacer) createUpdateClusterManifests(ctx context.Context, manifestN
 			Annotations: map[string]string{"kuadrant.io/parent": key},
 		},
 	}
+	if token := upstream.GetAnnotations()[ResyncAnnotation]; token != "" {
+		work.Annotations[ResyncAnnotation] = token
+		obj = withResyncToken(obj, token)
+	}
+	if op.encryptSecrets {
+		obj, err = op.encryptClusterSecrets(ctx, cluster, manifestName, obj)
+		if err != nil {
+			return nil, err
+		}
+	}
 	objManifests, err := op.manifest(obj...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.V(3).Info("placement:", "manifests prepared", len(objManifests))
 
@@ -312,19 +547,181 @@ func (op *ocmPlacer) createUpdateClusterManifests(ctx context.Context, manifestN
 			Name: "addresses",
 			Path: ".status.addresses",
 		},
+		{
+			Name: "conditions",
+			Path: ".status.conditions",
+		},
 	}
 	for _, l := range upstream.Spec.Listeners {
 		jsonPaths = append(jsonPaths, workv1.JsonPath{
 			Name: fmt.Sprintf("listener%sAttachedRoutes", l.Name),
 			Path: fmt.Sprintf(".status.listeners[?(@.name==\"%s\")].attachedRoutes", l.Name),
+		}, workv1.JsonPath{
+			Name: fmt.Sprintf("listener%sConditions", l.Name),
+			Path: fmt.Sprintf(".status.listeners[?(@.name==\"%s\")].conditions", l.Name),
 		})
 	}
 
 	work.Spec.ManifestConfigs[0].FeedbackRules[0].JsonPaths = jsonPaths
+	// feed back the annotations of the secrets as applied so the hub can tell whether the cluster is up to date
+	for _, o := range obj {
+		if _, ok := o.(*v1.Secret); !ok {
+			continue
+		}
+		work.Spec.ManifestConfigs = append(work.Spec.ManifestConfigs, workv1.ManifestConfigOption{
+			ResourceIdentifier: workv1.ResourceIdentifier{
+				Group:     "",
+				Resource:  "secrets",
+				Name:      o.GetName(),
+				Namespace: o.GetNamespace(),
+			},
+			FeedbackRules: []workv1.FeedbackRule{
+				{
+					Type: workv1.JSONPathsType,
+					JsonPaths: []workv1.JsonPath{
+						{
+							Name: "annotations",
+							Path: ".metadata.annotations",
+						},
+					},
+				},
+			},
+		})
+	}
+	if downstream.GetAnnotations()[UnmanagedNamespaceAnnotation] == "true" {
+		unmanagedNamespaces(&work, obj...)
+	}
 	log.V(3).Info("feedback rules set ", "feedback ", work.Spec.ManifestConfigs[0].FeedbackRules)
-	log.V(3).Info("placement: creating updating maniftests for ", "cluster", cluster)
-	return op.createUpdateManifest(ctx, cluster, work)
+	return &work, nil
+}
 
+// withResyncToken returns copies of the objects annotated with the resync token
+func withResyncToken(objs []metav1.Object, token string) []metav1.Object {
+	stamped := []metav1.Object{}
+	for _, o := range objs {
+		if copier, ok := o.(runtime.Object); ok {
+			if copied, ok := copier.DeepCopyObject().(metav1.Object); ok {
+				o = copied
+			}
+		}
+		annotations := map[string]string{}
+		for key, value := range o.GetAnnotations() {
+			annotations[key] = value
+		}
+		annotations[ResyncAnnotation] = token
+		o.SetAnnotations(annotations)
+		stamped = append(stamped, o)
+	}
+	return stamped
+}
+
+// orphan keeps the resources matching the rules in the cluster when they are removed from the work or the work is
+// deleted
+func orphan(work *workv1.ManifestWork, rules ...workv1.OrphaningRule) {
+	if work.Spec.DeleteOption == nil {
+		work.Spec.DeleteOption = &workv1.DeleteOption{
+			PropagationPolicy: workv1.DeletePropagationPolicyTypeSelectivelyOrphan,
+			SelectivelyOrphan: &workv1.SelectivelyOrphan{},
+		}
+	}
+	work.Spec.DeleteOption.SelectivelyOrphan.OrphaningRules = append(work.Spec.DeleteOption.SelectivelyOrphan.OrphaningRules, rules...)
+}
+
+// unmanagedNamespaces configures the work so that the namespaces of the objects are only created when missing and are
+// orphaned rather than deleted when the work is deleted or the objects are removed from it
+func unmanagedNamespaces(work *workv1.ManifestWork, objs ...metav1.Object) {
+	namespaces := sets.New[string]()
+	for _, o := range objs {
+		namespaces.Insert(o.GetNamespace())
+	}
+	orphaningRules := []workv1.OrphaningRule{}
+	for _, namespace := range sets.List(namespaces) {
+		work.Spec.ManifestConfigs = append(work.Spec.ManifestConfigs, workv1.ManifestConfigOption{
+			ResourceIdentifier: workv1.ResourceIdentifier{
+				Group:    "",
+				Resource: "namespaces",
+				Name:     namespace,
+			},
+			UpdateStrategy: &workv1.UpdateStrategy{Type: workv1.UpdateStrategyTypeCreateOnly},
+		})
+		orphaningRules = append(orphaningRules, workv1.OrphaningRule{
+			Group:    "",
+			Resource: "namespaces",
+			Name:     namespace,
+		})
+	}
+	orphan(work, orphaningRules...)
+}
+
+// hasSecrets returns whether any of the objects is a secret
+func hasSecrets(objs []metav1.Object) bool {
+	for _, obj := range objs {
+		if _, ok := obj.(*v1.Secret); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterPublicKey returns the public key the secrets placed on the cluster are encrypted with. An error wrapping
+// ErrClusterRefused is returned when the cluster has not published a valid key
+func (op *ocmPlacer) clusterPublicKey(ctx context.Context, cluster string) (*ecdh.PublicKey, error) {
+	managedCluster := &clusterv1.ManagedCluster{}
+	if err := op.c.Get(ctx, client.ObjectKey{Name: cluster}, managedCluster); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: ManagedCluster %s not found to encrypt secrets for", ErrClusterRefused, cluster)
+		}
+		return nil, err
+	}
+	publicKey, err := envelope.ClusterPublicKey(managedCluster)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrClusterRefused, err)
+	}
+	return publicKey, nil
+}
+
+// encryptClusterSecrets replaces the secrets with their encrypted counterpart for the cluster. Secrets that have
+// not changed keep the ciphertext already in the ManifestWork so the ManifestWork is not updated on every reconcile
+func (op *ocmPlacer) encryptClusterSecrets(ctx context.Context, cluster, manifestName string, objs []metav1.Object) ([]metav1.Object, error) {
+	if !hasSecrets(objs) {
+		return objs, nil
+	}
+	publicKey, err := op.clusterPublicKey(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]*v1.Secret{}
+	mw := &workv1.ManifestWork{}
+	if err := op.c.Get(ctx, client.ObjectKey{Namespace: cluster, Name: manifestName}, mw); client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+	for _, m := range mw.Spec.Workload.Manifests {
+		secret := &v1.Secret{}
+		if err := json.Unmarshal(m.Raw, secret); err != nil || secret.Kind != "Secret" || secret.Type != envelope.SecretType {
+			continue
+		}
+		existing[fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)] = secret
+	}
+
+	encrypted := []metav1.Object{}
+	for _, obj := range objs {
+		secret, ok := obj.(*v1.Secret)
+		if !ok {
+			encrypted = append(encrypted, obj)
+			continue
+		}
+		encryptedSecret, err := envelope.EncryptSecret(secret, publicKey, op.hashKey)
+		if err != nil {
+			return nil, err
+		}
+		previous, ok := existing[fmt.Sprintf("%s/%s", encryptedSecret.Namespace, encryptedSecret.Name)]
+		if ok && previous.Annotations[envelope.PayloadHashAnnotation] == encryptedSecret.Annotations[envelope.PayloadHashAnnotation] {
+			encryptedSecret.Data = previous.Data
+		}
+		encrypted = append(encrypted, encryptedSecret)
+	}
+	return encrypted, nil
 }
 
 func (op *ocmPlacer) manifest(obj ...metav1.Object) ([]workv1.Manifest, error) {
@@ -421,10 +818,12 @@ func (op *ocmPlacer) defaultRBAC(ctx context.Context, clusterName string) error
 			Workload: manifests,
 		},
 	}
-	return op.createUpdateManifest(ctx, clusterName, work)
+	_, err = op.createUpdateManifest(ctx, clusterName, work)
+	return err
 }
 
-func (op *ocmPlacer) createUpdateManifest(ctx context.Context, cluster string, m workv1.ManifestWork) error {
+// createUpdateManifest creates or updates the ManifestWork, returning whether it was created
+func (op *ocmPlacer) createUpdateManifest(ctx context.Context, cluster string, m workv1.ManifestWork) (bool, error) {
 	mw := &workv1.ManifestWork{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      m.Name,
@@ -435,20 +834,37 @@ func (op *ocmPlacer) createUpdateManifest(ctx context.Context, cluster string, m
 		if k8serrors.IsNotFound(err) {
 			log.Log.V(3).Info("placement: manifest not found creating it ", "cluster", mw.Namespace)
 			if err := op.c.Create(ctx, &m, &client.CreateOptions{}); err != nil {
-				return err
+				recordApplyFailure(cluster)
+				return false, err
 			}
-			return nil
+			return true, nil
 		}
 	}
 
-	if !equality.Semantic.DeepEqual(mw.Spec, m.Spec) {
+	// the resync token of the work is compared as well so that the work records the token its manifests carry
+	token := m.GetAnnotations()[ResyncAnnotation]
+	if !equality.Semantic.DeepEqual(mw.Spec, m.Spec) || mw.GetAnnotations()[ResyncAnnotation] != token {
 		log.Log.V(3).Info("placement: manifest found updating it ")
 		mw.Spec = m.Spec
+		if token != "" {
+			metadata.AddAnnotation(mw, ResyncAnnotation, token)
+		} else {
+			metadata.RemoveAnnotation(mw, ResyncAnnotation)
+		}
 		if err := op.c.Update(ctx, mw, &client.UpdateOptions{}); err != nil {
 			log.Log.V(3).Info("placement:  updating manifest ", "error", err)
-			return err
+			recordApplyFailure(cluster)
+			return false, err
 		}
 	}
 
-	return nil
+	return false, nil
+}
+
+// eventf records an event on the gateway when the placer has an event recorder
+func (op *ocmPlacer) eventf(gateway *gatewayapiv1.Gateway, eventType, reason, messageFmt string, args ...interface{}) {
+	if op.recorder == nil {
+		return
+	}
+	op.recorder.Eventf(gateway, eventType, reason, messageFmt, args...)
 }
@@ -5,8 +5,13 @@ package placement_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	pd "open-cluster-management.io/api/cluster/v1beta1"
 	workv1 "open-cluster-management.io/api/work/v1"
 
@@ -16,10 +21,13 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/_internal/gracePeriod"
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/ocm/envelope"
 	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 )
 
@@ -30,6 +38,9 @@ func init() {
 	if err := pd.AddToScheme(scheme.Scheme); err != nil {
 		panic(err)
 	}
+	if err := clusterv1.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
 }
 
 func TestGetAddresses(t *testing.T) {
@@ -330,6 +341,170 @@ func TestListenerTotalAttachedRoutes(t *testing.T) {
 	}
 }
 
+func TestGetConditions(t *testing.T) {
+	conditionsJson, err := json.Marshal([]v1.Condition{
+		{
+			Type:    string(gatewayapiv1.GatewayConditionProgrammed),
+			Status:  v1.ConditionFalse,
+			Reason:  string(gatewayapiv1.GatewayReasonAddressNotAssigned),
+			Message: "no address assigned",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conditionsJsonString := string(conditionsJson)
+	gateway := &gatewayapiv1.Gateway{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/gatewayapiv1",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test",
+		},
+	}
+
+	testCases := []struct {
+		Name     string
+		Feedback []workv1.FeedbackValue
+		Assert   func(t *testing.T, err error, conditions []v1.Condition)
+	}{
+		{
+			Name: "test conditions decoded from feedback",
+			Feedback: []workv1.FeedbackValue{
+				{
+					Name: "conditions",
+					Value: workv1.FieldValue{
+						Type:    workv1.JsonRaw,
+						JsonRaw: &conditionsJsonString,
+					},
+				},
+			},
+			Assert: func(t *testing.T, err error, conditions []v1.Condition) {
+				if err != nil {
+					t.Fatalf("did not expect an error but got one %s", err)
+				}
+				if len(conditions) != 1 || conditions[0].Message != "no address assigned" {
+					t.Fatalf("expected the programmed condition but got %v", conditions)
+				}
+			},
+		},
+		{
+			Name:     "test error when no conditions feedback",
+			Feedback: []workv1.FeedbackValue{},
+			Assert: func(t *testing.T, err error, conditions []v1.Condition) {
+				if err == nil {
+					t.Fatalf("expected an error but got none")
+				}
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			mw := &workv1.ManifestWork{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      placement.WorkName(gateway),
+					Namespace: "test",
+				},
+				Status: workv1.ManifestWorkStatus{
+					ResourceStatus: workv1.ManifestResourceStatus{
+						Manifests: []workv1.ManifestCondition{
+							{
+								ResourceMeta: workv1.ManifestResourceMeta{
+									Group: "gateway.networking.k8s.io",
+									Name:  gateway.Name,
+								},
+								StatusFeedbacks: workv1.StatusFeedbackResult{
+									Values: testCase.Feedback,
+								},
+							},
+						},
+					},
+				},
+			}
+			p := placement.NewOCMPlacer(fake.NewClientBuilder().WithObjects(mw).Build())
+			conditions, err := p.GetConditions(context.TODO(), gateway, "test")
+			testCase.Assert(t, err, conditions)
+		})
+	}
+}
+
+func TestGetSecretAnnotations(t *testing.T) {
+	plainAnnotations := `{"kuadrant.io/tls-fingerprint":"abc"}`
+	encryptedAnnotations := `{"kuadrant.io/tls-fingerprint":"def","kuadrant.io/decrypted-name":"encrypted"}`
+	gateway := &gatewayapiv1.Gateway{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/gatewayapiv1",
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	mw := &workv1.ManifestWork{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      placement.WorkName(gateway),
+			Namespace: "test",
+		},
+		Status: workv1.ManifestWorkStatus{
+			ResourceStatus: workv1.ManifestResourceStatus{
+				Manifests: []workv1.ManifestCondition{
+					{
+						ResourceMeta: workv1.ManifestResourceMeta{
+							Group: "gateway.networking.k8s.io",
+							Kind:  "Gateway",
+							Name:  gateway.Name,
+						},
+					},
+					{
+						ResourceMeta: workv1.ManifestResourceMeta{
+							Kind: "Secret",
+							Name: "plain",
+						},
+						StatusFeedbacks: workv1.StatusFeedbackResult{
+							Values: []workv1.FeedbackValue{
+								{
+									Name:  "annotations",
+									Value: workv1.FieldValue{Type: workv1.JsonRaw, JsonRaw: &plainAnnotations},
+								},
+							},
+						},
+					},
+					{
+						ResourceMeta: workv1.ManifestResourceMeta{
+							Kind: "Secret",
+							Name: "encrypted-encrypted",
+						},
+						StatusFeedbacks: workv1.StatusFeedbackResult{
+							Values: []workv1.FeedbackValue{
+								{
+									Name:  "annotations",
+									Value: workv1.FieldValue{Type: workv1.JsonRaw, JsonRaw: &encryptedAnnotations},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	p := placement.NewOCMPlacer(fake.NewClientBuilder().WithObjects(mw).Build())
+	annotations, err := p.GetSecretAnnotations(context.TODO(), gateway, "test")
+	if err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	if len(annotations) != 2 {
+		t.Fatalf("expected annotations of 2 secrets but got %v", annotations)
+	}
+	if annotations["plain"]["kuadrant.io/tls-fingerprint"] != "abc" {
+		t.Fatalf("expected the annotations of the plain secret but got %v", annotations["plain"])
+	}
+	if annotations["encrypted"]["kuadrant.io/tls-fingerprint"] != "def" {
+		t.Fatalf("expected the encrypted secret under its decrypted name but got %v", annotations)
+	}
+}
+
 func TestGetPlacedClusters(t *testing.T) {
 	testCases := []struct {
 		Name               string
@@ -683,7 +858,7 @@ func TestDeschedule(t *testing.T) {
 			p := placement.NewOCMPlacer(c)
 			// build a test function as we want to change state and execute twice
 
-			placed, err := p.Place(context.TODO(), testCase.Upstream, testCase.Downstream, testCase.TLSSecrets...)
+			placed, err := p.Place(context.TODO(), testCase.Upstream, testCase.Downstream, nil, testCase.TLSSecrets...)
 			if placed != nil && !placed.Equal(testCase.Clusters) {
 				t.Fatalf("expected placed clusters %v to equal the target clusters %v", placed.UnsortedList(), testCase.Clusters.UnsortedList())
 			}
@@ -708,3 +883,648 @@ func TestDeschedule(t *testing.T) {
 		})
 	}
 }
+
+func TestPlaceWithSecretEncryption(t *testing.T) {
+	key, err := envelope.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/gatewayapiv1",
+		},
+	}
+	downstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "kuadrant-test",
+			Name:      "test",
+		},
+		TypeMeta: upstream.TypeMeta,
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-tls",
+			Namespace: "kuadrant-test",
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: []byte("private-key"),
+		},
+	}
+	placementDecision := &pd.PlacementDecision{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		Status: pd.PlacementDecisionStatus{
+			Decisions: []pd.ClusterDecision{{ClusterName: "c1"}, {ClusterName: "c2"}},
+		},
+	}
+	keyClaims := []clusterv1.ManagedClusterClaim{{Name: envelope.PublicKeyClaim, Value: envelope.EncodePublicKey(key.PublicKey())}}
+	// c2 always publishes its key so that a refusal of c1 is shown not to affect it
+	otherCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: v1.ObjectMeta{Name: "c2"},
+		Status:     clusterv1.ManagedClusterStatus{ClusterClaims: keyClaims},
+	}
+	hashKey, err := envelope.GenerateHashKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name        string
+		Claims      []clusterv1.ManagedClusterClaim
+		Children    []v1.Object
+		WantTargets []string
+		WantRefused bool
+	}{
+		{
+			Name:        "test secrets are encrypted with the cluster key",
+			Claims:      keyClaims,
+			Children:    []v1.Object{secret},
+			WantTargets: []string{"c1", "c2"},
+		},
+		{
+			Name:        "test a cluster with no key is refused without affecting the others",
+			Children:    []v1.Object{secret},
+			WantTargets: []string{"c2"},
+			WantRefused: true,
+		},
+		{
+			Name:        "test a cluster with no key is placed on when the gateway has no secrets",
+			WantTargets: []string{"c1", "c2"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: v1.ObjectMeta{Name: "c1"},
+				Status:     clusterv1.ManagedClusterStatus{ClusterClaims: testCase.Claims},
+			}
+			c := fake.NewClientBuilder().WithObjects(placementDecision, managedCluster, otherCluster).Build()
+			p := placement.NewOCMPlacer(c, placement.WithSecretEncryption(hashKey))
+
+			targets, err := p.Place(context.TODO(), upstream, downstream, nil, testCase.Children...)
+			if err != nil {
+				t.Fatalf("did not expect an error but got one %s", err)
+			}
+			if !targets.Equal(sets.New(testCase.WantTargets...)) {
+				t.Fatalf("expected targets %v but got %v", testCase.WantTargets, sets.List(targets))
+			}
+			plan, err := p.Plan(context.TODO(), upstream, downstream, nil, testCase.Children...)
+			if err != nil {
+				t.Fatalf("did not expect an error planning but got one %s", err)
+			}
+			if refused := plan.ClustersWith(placement.PlanRefuse); (len(refused) == 1 && refused[0] == "c1") != testCase.WantRefused {
+				t.Fatalf("expected c1 refused %v but got refused clusters %v", testCase.WantRefused, refused)
+			}
+			mw := &workv1.ManifestWork{}
+			err = c.Get(context.TODO(), client.ObjectKey{Namespace: "c1", Name: placement.WorkName(upstream)}, mw)
+			if testCase.WantRefused {
+				if !k8serrors.IsNotFound(err) {
+					t.Fatalf("expected no manifest work on the refused cluster but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect an error getting the manifest work but got one %s", err)
+			}
+			if len(testCase.Children) == 0 {
+				return
+			}
+			var encrypted *corev1.Secret
+			for _, m := range mw.Spec.Workload.Manifests {
+				if strings.Contains(string(m.Raw), "private-key") || strings.Contains(string(m.Raw), "cHJpdmF0ZS1rZXk=") {
+					t.Fatalf("expected the manifest work not to contain the secret data")
+				}
+				s := &corev1.Secret{}
+				if err := json.Unmarshal(m.Raw, s); err == nil && s.Type == envelope.SecretType {
+					encrypted = s
+				}
+			}
+			if encrypted == nil {
+				t.Fatalf("expected an encrypted secret in the manifest work")
+			}
+			decrypted, err := envelope.DecryptSecret(encrypted, key)
+			if err != nil || string(decrypted.Data[corev1.TLSPrivateKeyKey]) != "private-key" {
+				t.Fatalf("expected the secret to decrypt with the cluster key, got %v %v", decrypted, err)
+			}
+
+			// placing the same secret again should not change the ciphertext
+			if _, err := p.Place(context.TODO(), upstream, downstream, nil, testCase.Children...); err != nil {
+				t.Fatalf("did not expect an error but got one %s", err)
+			}
+			updated := &workv1.ManifestWork{}
+			if err := c.Get(context.TODO(), client.ObjectKeyFromObject(mw), updated); err != nil {
+				t.Fatalf("did not expect an error getting the manifest work but got one %s", err)
+			}
+			if updated.ResourceVersion != mw.ResourceVersion {
+				t.Fatalf("expected the manifest work not to be updated when the secret is unchanged")
+			}
+		})
+	}
+}
+
+func TestPlaceInUnmanagedNamespace(t *testing.T) {
+	upstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/gatewayapiv1",
+		},
+	}
+	placementDecision := &pd.PlacementDecision{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		Status: pd.PlacementDecisionStatus{
+			Decisions: []pd.ClusterDecision{{ClusterName: "c1"}},
+		},
+	}
+
+	testCases := []struct {
+		Name        string
+		Annotations map[string]string
+		WantOrphan  bool
+	}{
+		{
+			Name:        "test namespace is orphaned when unmanaged",
+			Annotations: map[string]string{placement.UnmanagedNamespaceAnnotation: "true"},
+			WantOrphan:  true,
+		},
+		{
+			Name: "test namespace is deleted with the work when managed",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			downstream := &gatewayapiv1.Gateway{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace:   "team-a",
+					Name:        "test",
+					Annotations: testCase.Annotations,
+				},
+				TypeMeta: upstream.TypeMeta,
+			}
+			c := fake.NewClientBuilder().WithObjects(placementDecision).Build()
+			p := placement.NewOCMPlacer(c)
+			if _, err := p.Place(context.TODO(), upstream, downstream, nil); err != nil {
+				t.Fatalf("did not expect an error but got one %s", err)
+			}
+			mw := &workv1.ManifestWork{}
+			if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "c1", Name: placement.WorkName(upstream)}, mw); err != nil {
+				t.Fatalf("did not expect an error getting the manifest work but got one %s", err)
+			}
+			if !testCase.WantOrphan {
+				if mw.Spec.DeleteOption != nil {
+					t.Fatalf("expected no delete option but got %v", mw.Spec.DeleteOption)
+				}
+				return
+			}
+			if mw.Spec.DeleteOption == nil || mw.Spec.DeleteOption.PropagationPolicy != workv1.DeletePropagationPolicyTypeSelectivelyOrphan {
+				t.Fatalf("expected the namespace to be selectively orphaned but got %v", mw.Spec.DeleteOption)
+			}
+			rules := mw.Spec.DeleteOption.SelectivelyOrphan.OrphaningRules
+			if len(rules) != 1 || rules[0].Resource != "namespaces" || rules[0].Name != "team-a" {
+				t.Errorf("expected an orphaning rule for namespace team-a but got %v", rules)
+			}
+			createOnly := false
+			for _, config := range mw.Spec.ManifestConfigs {
+				if config.ResourceIdentifier.Resource == "namespaces" && config.ResourceIdentifier.Name == "team-a" {
+					createOnly = config.UpdateStrategy != nil && config.UpdateStrategy.Type == workv1.UpdateStrategyTypeCreateOnly
+				}
+			}
+			if !createOnly {
+				t.Errorf("expected namespace team-a to be create only but got %v", mw.Spec.ManifestConfigs)
+			}
+		})
+	}
+}
+
+func TestPlaceWithClusterCustomiser(t *testing.T) {
+	hostname := gatewayapiv1.Hostname("api.example.com")
+	upstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/gatewayapiv1",
+		},
+	}
+	downstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "kuadrant-test",
+			Name:      "test",
+		},
+		TypeMeta: upstream.TypeMeta,
+		Spec: gatewayapiv1.GatewaySpec{
+			Listeners: []gatewayapiv1.Listener{{Name: "api", Hostname: &hostname}},
+		},
+	}
+	placementDecision := &pd.PlacementDecision{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		Status: pd.PlacementDecisionStatus{
+			Decisions: []pd.ClusterDecision{{ClusterName: "c1"}, {ClusterName: "c2"}},
+		},
+	}
+	customise := func(_ context.Context, cluster string, gateway *gatewayapiv1.Gateway) error {
+		hostname := gatewayapiv1.Hostname(cluster + ".example.com")
+		gateway.Spec.Listeners[0].Hostname = &hostname
+		return nil
+	}
+
+	c := fake.NewClientBuilder().WithObjects(placementDecision).Build()
+	p := placement.NewOCMPlacer(c)
+	if _, err := p.Place(context.TODO(), upstream, downstream, customise); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	for _, cluster := range []string{"c1", "c2"} {
+		mw := &workv1.ManifestWork{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Namespace: cluster, Name: placement.WorkName(upstream)}, mw); err != nil {
+			t.Fatalf("did not expect an error getting the manifest work but got one %s", err)
+		}
+		placed := &gatewayapiv1.Gateway{}
+		if err := json.Unmarshal(mw.Spec.Workload.Manifests[0].Raw, placed); err != nil {
+			t.Fatalf("did not expect an error decoding the placed gateway but got one %s", err)
+		}
+		if hostname := string(*placed.Spec.Listeners[0].Hostname); hostname != cluster+".example.com" {
+			t.Errorf("expected hostname %s.example.com on cluster %s but got %s", cluster, cluster, hostname)
+		}
+	}
+	if hostname := string(*downstream.Spec.Listeners[0].Hostname); hostname != "api.example.com" {
+		t.Errorf("expected the downstream gateway to be left unchanged but got hostname %s", hostname)
+	}
+
+	failing := func(_ context.Context, _ string, _ *gatewayapiv1.Gateway) error {
+		return fmt.Errorf("invalid override")
+	}
+	if _, err := p.Place(context.TODO(), upstream, downstream, failing); err == nil {
+		t.Errorf("expected an error when the gateway can't be customised")
+	}
+}
+
+func TestPlaceWithRefusedCluster(t *testing.T) {
+	upstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/gatewayapiv1",
+		},
+	}
+	downstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "kuadrant-test",
+			Name:      "test",
+		},
+		TypeMeta: upstream.TypeMeta,
+	}
+	placementDecision := &pd.PlacementDecision{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		Status: pd.PlacementDecisionStatus{
+			Decisions: []pd.ClusterDecision{{ClusterName: "c1"}, {ClusterName: "c2"}},
+		},
+	}
+	refuse := func(_ context.Context, cluster string, _ *gatewayapiv1.Gateway) error {
+		if cluster == "c2" {
+			return fmt.Errorf("%w: missing gateway class istio", placement.ErrClusterRefused)
+		}
+		return nil
+	}
+
+	c := fake.NewClientBuilder().WithObjects(placementDecision).Build()
+	p := placement.NewOCMPlacer(c)
+	if _, err := p.Place(context.TODO(), upstream, downstream, nil); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	// the gateway is removed from a cluster that no longer has the capabilities to run it
+	placed, err := p.Place(context.TODO(), upstream, downstream, refuse)
+	if err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	if !placed.Equal(sets.New("c1")) {
+		t.Errorf("expected the gateway to be placed on c1 only but got %v", placed.UnsortedList())
+	}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "c2", Name: placement.WorkName(upstream)}, &workv1.ManifestWork{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the manifest work to be removed from the refused cluster but got %v", err)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	upstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/gatewayapiv1",
+		},
+	}
+	downstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "kuadrant-test",
+			Name:      "test",
+		},
+		TypeMeta: upstream.TypeMeta,
+	}
+	secret := &corev1.Secret{
+		TypeMeta:   v1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: v1.ObjectMeta{Namespace: "kuadrant-test", Name: "tls"},
+		Data:       map[string][]byte{"tls.key": []byte("private")},
+	}
+	placementDecision := &pd.PlacementDecision{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		Status: pd.PlacementDecisionStatus{
+			Decisions: []pd.ClusterDecision{{ClusterName: "c1"}, {ClusterName: "c3"}, {ClusterName: "c5"}},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(placementDecision).Build()
+	p := placement.NewOCMPlacer(c)
+	if _, err := p.Place(context.TODO(), upstream, downstream, nil, secret); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	// the gateway is only placed once its work is applied
+	removed := &workv1.ManifestWork{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "c3", Name: placement.WorkName(upstream)}, removed); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	removed.Status.Conditions = []v1.Condition{{Type: workv1.WorkApplied, Status: v1.ConditionTrue, Reason: "Applied"}}
+	if err := c.Update(context.TODO(), removed); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+
+	placementDecision.Status.Decisions = []pd.ClusterDecision{{ClusterName: "c1"}, {ClusterName: "c2"}, {ClusterName: "c4"}, {ClusterName: "c5"}}
+	if err := c.Update(context.TODO(), placementDecision); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	customise := func(_ context.Context, cluster string, gateway *gatewayapiv1.Gateway) error {
+		switch cluster {
+		case "c1":
+			gateway.Labels = map[string]string{"changed": "true"}
+		case "c4":
+			return fmt.Errorf("%w: missing gateway class istio", placement.ErrClusterRefused)
+		}
+		return nil
+	}
+	plan, err := p.Plan(context.TODO(), upstream, downstream, customise, secret)
+	if err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+
+	expected := map[string]placement.PlanAction{
+		"c1": placement.PlanUpdate,
+		"c2": placement.PlanAdd,
+		"c3": placement.PlanRemove,
+		"c4": placement.PlanRefuse,
+		"c5": placement.PlanUnchanged,
+	}
+	if len(plan.Clusters) != len(expected) {
+		t.Fatalf("expected a plan for %d clusters but got %v", len(expected), plan.Clusters)
+	}
+	for i, cluster := range plan.Clusters {
+		if i > 0 && plan.Clusters[i-1].Cluster > cluster.Cluster {
+			t.Errorf("expected the plan ordered by cluster but got %s before %s", plan.Clusters[i-1].Cluster, cluster.Cluster)
+		}
+		if cluster.Action != expected[cluster.Cluster] {
+			t.Errorf("expected action %s for cluster %s but got %s", expected[cluster.Cluster], cluster.Cluster, cluster.Action)
+		}
+		for _, manifest := range cluster.Manifests {
+			if strings.Contains(string(manifest.Raw), "tls.key") {
+				t.Errorf("expected the secret data left out of the plan for cluster %s but got %s", cluster.Cluster, manifest.Raw)
+			}
+		}
+	}
+	if !plan.HasChanges() {
+		t.Errorf("expected the plan to have changes")
+	}
+	if added := plan.ClustersWith(placement.PlanAdd); len(added) != 1 || added[0] != "c2" {
+		t.Errorf("expected c2 to be added but got %v", added)
+	}
+
+	// the plan writes no ManifestWork
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "c2", Name: placement.WorkName(upstream)}, &workv1.ManifestWork{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected no manifest work for the added cluster but got %v", err)
+	}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "c3", Name: placement.WorkName(upstream)}, &workv1.ManifestWork{}); err != nil {
+		t.Errorf("expected the manifest work of the removed cluster to remain but got %v", err)
+	}
+	updated := &workv1.ManifestWork{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "c1", Name: placement.WorkName(upstream)}, updated); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	if strings.Contains(string(updated.Spec.Workload.Manifests[0].Raw), "changed") {
+		t.Errorf("expected the manifest work of the updated cluster to be unchanged")
+	}
+}
+
+func TestPlaceWithResyncToken(t *testing.T) {
+	upstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/gatewayapiv1",
+		},
+	}
+	downstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "kuadrant-test",
+			Name:      "test",
+		},
+		TypeMeta: upstream.TypeMeta,
+	}
+	placementDecision := &pd.PlacementDecision{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		Status: pd.PlacementDecisionStatus{
+			Decisions: []pd.ClusterDecision{{ClusterName: "c1"}},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(placementDecision).Build()
+	p := placement.NewOCMPlacer(c)
+	key := client.ObjectKey{Namespace: "c1", Name: placement.WorkName(upstream)}
+	getWork := func() *workv1.ManifestWork {
+		work := &workv1.ManifestWork{}
+		if err := c.Get(context.TODO(), key, work); err != nil {
+			t.Fatalf("did not expect an error but got one %s", err)
+		}
+		return work
+	}
+
+	if _, err := p.Place(context.TODO(), upstream, downstream, nil); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	placed := getWork()
+	if _, err := p.Place(context.TODO(), upstream, downstream, nil); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	if unchanged := getWork(); unchanged.ResourceVersion != placed.ResourceVersion {
+		t.Errorf("did not expect the manifest work to be updated without a resync token")
+	}
+
+	upstream.Annotations = map[string]string{placement.ResyncAnnotation: "1"}
+	if _, err := p.Place(context.TODO(), upstream, downstream, nil); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	resynced := getWork()
+	if resynced.ResourceVersion == placed.ResourceVersion {
+		t.Errorf("expected the manifest work to be updated by a new resync token")
+	}
+	if resynced.Annotations[placement.ResyncAnnotation] != "1" {
+		t.Errorf("expected the manifest work to record the resync token but got %v", resynced.Annotations)
+	}
+	if !strings.Contains(string(resynced.Spec.Workload.Manifests[0].Raw), placement.ResyncAnnotation) {
+		t.Errorf("expected the placed gateway to carry the resync token but got %s", resynced.Spec.Workload.Manifests[0].Raw)
+	}
+	if downstream.Annotations != nil {
+		t.Errorf("did not expect the downstream gateway to be changed but got %v", downstream.Annotations)
+	}
+
+	// the same token doesn't update the work again
+	if _, err := p.Place(context.TODO(), upstream, downstream, nil); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	if again := getWork(); again.ResourceVersion != resynced.ResourceVersion {
+		t.Errorf("did not expect the manifest work to be updated again with the same resync token")
+	}
+}
+
+func TestPlaceRecordsEvents(t *testing.T) {
+	upstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		TypeMeta: v1.TypeMeta{
+			Kind:       "Gateway",
+			APIVersion: "gateway.networking.k8s.io/gatewayapiv1",
+		},
+	}
+	downstream := &gatewayapiv1.Gateway{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "kuadrant-test",
+			Name:      "test",
+		},
+		TypeMeta: upstream.TypeMeta,
+	}
+	placementDecision := &pd.PlacementDecision{
+		ObjectMeta: v1.ObjectMeta{
+			Labels:    map[string]string{placement.OCMPlacementLabel: "test"},
+			Namespace: "test",
+			Name:      "test",
+		},
+		Status: pd.PlacementDecisionStatus{
+			Decisions: []pd.ClusterDecision{{ClusterName: "c1"}},
+		},
+	}
+	placedWork := func(cluster string) *workv1.ManifestWork {
+		return &workv1.ManifestWork{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      placement.WorkName(upstream),
+				Namespace: cluster,
+				Labels:    map[string]string{placement.WorkManifestLabel: placement.WorkName(upstream)},
+			},
+			Status: workv1.ManifestWorkStatus{
+				Conditions: []v1.Condition{{Type: workv1.WorkApplied, Status: metav1.ConditionTrue, Reason: "Applied"}},
+			},
+		}
+	}
+	c := fake.NewClientBuilder().
+		WithObjects(placementDecision, placedWork("c2"), &clusterv1.ManagedCluster{ObjectMeta: v1.ObjectMeta{Name: "c2"}}).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	p := placement.NewOCMPlacer(c, placement.WithEventRecorder(recorder))
+	expectEvents := func(expected ...string) {
+		t.Helper()
+		for _, event := range expected {
+			select {
+			case got := <-recorder.Events:
+				if got != event {
+					t.Errorf("expected event %q but got %q", event, got)
+				}
+			default:
+				t.Errorf("expected event %q but got none", event)
+			}
+		}
+		select {
+		case got := <-recorder.Events:
+			t.Errorf("did not expect event %q", got)
+		default:
+		}
+	}
+
+	// the removal from c2 starts its grace period
+	if _, err := p.Place(context.TODO(), upstream, downstream, nil); !errors.Is(err, gracePeriod.ErrGracePeriodNotExpired) {
+		t.Fatalf("expected the grace period not to be expired but got %v", err)
+	}
+	expectEvents(
+		"Normal ClusterAdded gateway placed on cluster c1",
+		fmt.Sprintf("Normal ClusterRemovalScheduled gateway removal from cluster c2 scheduled after a grace period of %s", gracePeriod.DefaultGracePeriod),
+	)
+
+	// the gateway is removed from c2 once the grace period expires
+	removed := &workv1.ManifestWork{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "c2", Name: placement.WorkName(upstream)}, removed); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	removed.Annotations[gracePeriod.GraceTimestampAnnotation] = fmt.Sprintf("%d", time.Now().Add(-time.Minute).Unix())
+	if err := c.Update(context.TODO(), removed); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	if _, err := p.Place(context.TODO(), upstream, downstream, nil); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	expectEvents(
+		"Normal GracePeriodExpired grace period of the gateway removal from cluster c2 expired",
+		"Normal ClusterRemoved gateway removed from cluster c2",
+	)
+
+	// the gateway is removed from a deleted cluster without a grace period
+	if err := c.Create(context.TODO(), placedWork("c3")); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	if _, err := p.Place(context.TODO(), upstream, downstream, nil); err != nil {
+		t.Fatalf("did not expect an error but got one %s", err)
+	}
+	expectEvents("Normal ClusterRemoved gateway removed from cluster c3 without a grace period as the ManagedCluster was not found")
+}
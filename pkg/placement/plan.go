@@ -0,0 +1,153 @@
+package placement
+
+import (
+	"context"
+	"sort"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// PlanAction is the change placing a gateway would make to a cluster
+type PlanAction string
+
+const (
+	// PlanAdd places the gateway on a cluster it is not placed on
+	PlanAdd PlanAction = "Add"
+	// PlanUpdate changes the ManifestWork of a cluster the gateway is placed on
+	PlanUpdate PlanAction = "Update"
+	// PlanUnchanged leaves the ManifestWork of the cluster as it is
+	PlanUnchanged PlanAction = "Unchanged"
+	// PlanRemove removes the gateway from the cluster
+	PlanRemove PlanAction = "Remove"
+	// PlanRefuse leaves a targeted cluster the gateway is not placed on without the gateway
+	PlanRefuse PlanAction = "Refuse"
+)
+
+// ClusterPlan is the change placing a gateway would make to a single cluster
+type ClusterPlan struct {
+	// Cluster is the name of the ManagedCluster
+	Cluster string `json:"cluster"`
+	// Action is the change made to the cluster
+	Action PlanAction `json:"action"`
+	// Reason explains why a cluster is refused or the gateway removed from it
+	Reason string `json:"reason,omitempty"`
+	// Manifests are the rendered manifests of the ManifestWork placed on the cluster, with the data of the secrets
+	// left out
+	Manifests []runtime.RawExtension `json:"manifests,omitempty"`
+}
+
+// Plan is the set of changes placing a gateway would make to the clusters, ordered by cluster name
+type Plan struct {
+	Clusters []ClusterPlan `json:"clusters"`
+}
+
+// ClustersWith returns the names of the clusters the plan makes the action to
+func (p *Plan) ClustersWith(action PlanAction) []string {
+	clusters := []string{}
+	for _, cluster := range p.Clusters {
+		if cluster.Action == action {
+			clusters = append(clusters, cluster.Cluster)
+		}
+	}
+	return clusters
+}
+
+// HasChanges returns whether placing the gateway would change any ManifestWork
+func (p *Plan) HasChanges() bool {
+	for _, cluster := range p.Clusters {
+		if cluster.Action != PlanUnchanged && cluster.Action != PlanRefuse {
+			return true
+		}
+	}
+	return false
+}
+
+// Plan returns the changes Place would make to each cluster without writing any ManifestWork
+func (op *ocmPlacer) Plan(ctx context.Context, upStreamGateway *gatewayapiv1.Gateway, downStreamGateway *gatewayapiv1.Gateway, customise ClusterCustomiser, children ...metav1.Object) (*Plan, error) {
+	workname := WorkName(upStreamGateway)
+	placementTargets, err := op.GetClusters(ctx, upStreamGateway)
+	if err != nil {
+		return nil, err
+	}
+	existingClusters, err := op.GetPlacedClusters(ctx, upStreamGateway)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := op.clusterChanges(ctx, workname, upStreamGateway, downStreamGateway, customise, placementTargets, existingClusters, children)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Clusters: []ClusterPlan{}}
+	for _, cluster := range sets.List(sets.KeySet(changes.place)) {
+		clusterGateway := changes.place[cluster]
+		objects := []metav1.Object{clusterGateway}
+		objects = append(objects, children...)
+		work, err := op.clusterWork(ctx, workname, upStreamGateway, clusterGateway, cluster, objects...)
+		if err != nil {
+			return nil, err
+		}
+		action := PlanUpdate
+		current := &workv1.ManifestWork{}
+		if err := op.c.Get(ctx, client.ObjectKey{Namespace: cluster, Name: workname}, current); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, err
+			}
+			action = PlanAdd
+		} else if equality.Semantic.DeepEqual(current.Spec, work.Spec) {
+			action = PlanUnchanged
+		}
+		manifests, err := redactSecrets(work.Spec.Workload.Manifests)
+		if err != nil {
+			return nil, err
+		}
+		plan.Clusters = append(plan.Clusters, ClusterPlan{Cluster: cluster, Action: action, Manifests: manifests})
+	}
+	for cluster, reason := range changes.refused {
+		if changes.removeFrom.Has(cluster) {
+			continue
+		}
+		plan.Clusters = append(plan.Clusters, ClusterPlan{Cluster: cluster, Action: PlanRefuse, Reason: reason})
+	}
+	for _, cluster := range changes.removeFrom.UnsortedList() {
+		reason, refused := changes.refused[cluster]
+		if !refused {
+			reason = "cluster no longer targeted by the placement"
+		}
+		plan.Clusters = append(plan.Clusters, ClusterPlan{Cluster: cluster, Action: PlanRemove, Reason: reason})
+	}
+	sort.Slice(plan.Clusters, func(i, j int) bool {
+		return plan.Clusters[i].Cluster < plan.Clusters[j].Cluster
+	})
+	return plan, nil
+}
+
+// redactSecrets returns the manifests with the data of the secrets left out so that the plan can be published
+func redactSecrets(manifests []workv1.Manifest) ([]runtime.RawExtension, error) {
+	redacted := []runtime.RawExtension{}
+	for _, manifest := range manifests {
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(manifest.Raw); err != nil {
+			return nil, err
+		}
+		if object.GetKind() == "Secret" {
+			unstructured.RemoveNestedField(object.Object, "data")
+			unstructured.RemoveNestedField(object.Object, "stringData")
+		}
+		raw, err := object.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		redacted = append(redacted, runtime.RawExtension{Raw: raw})
+	}
+	return redacted, nil
+}
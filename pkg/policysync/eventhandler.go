@@ -18,7 +18,13 @@ type ResourceEventHandler struct {
 	GVR           schema.GroupVersionResource
 	Client        client.Client
 	DynamicClient dynamic.Interface
-	Gateway       *gatewayapiv1.Gateway
+	// Downstream resolves the gateway targeted by the policy when the event is received, and the downstream gateway
+	// the policy is synced with. nil gateways are returned when the policy doesn't target a gateway placed by the
+	// controller, the policy is then synced unchanged
+	Downstream func(ctx context.Context, policy Policy) (upstream, downstream *gatewayapiv1.Gateway, err error)
+	// Metadata sets the labels and annotations of the policies synced to the spokes. It is called for each event as
+	// the rules depend on the class of the gateway targeted by the policy
+	Metadata func(ctx context.Context, policy Policy) error
 
 	Syncer Syncer
 }
@@ -40,15 +46,34 @@ func (h *ResourceEventHandler) OnAdd(reqObj interface{}, _ bool) {
 		h.Log.Error(err, "failed to get object", "object", obj)
 	}
 
-	policy, err := NewPolicyFor(obj)
+	policy, err := NewPolicyFor(obj.DeepCopyObject())
 	if err != nil {
 		h.Log.Error(err, "failed to build policy from watched object", "object", obj)
+		recordSync(h.Syncer, h.GVR, err)
 		return
 	}
+	upstream, downstream, err := h.Downstream(ctx, policy)
+	if err != nil {
+		h.Log.Error(err, "failed to resolve the gateway targeted by the policy", "policy", policy)
+		recordSync(h.Syncer, h.GVR, err)
+		return
+	}
+	if upstream != nil {
+		ForDownstream(policy, upstream, downstream)
+	}
+	if h.Metadata != nil {
+		if err := h.Metadata(ctx, policy); err != nil {
+			h.Log.Error(err, "failed to set the metadata of the policy", "policy", policy)
+			recordSync(h.Syncer, h.GVR, err)
+			return
+		}
+	}
 
-	if err := h.Syncer.SyncPolicy(ctx, h.Client, policy); err != nil {
+	err = h.Syncer.SyncPolicy(ctx, h.Client, policy)
+	if err != nil {
 		h.Log.Error(err, "failed to sync policy", "policy", policy)
 	}
+	recordSync(h.Syncer, h.GVR, err)
 }
 
 func (h *ResourceEventHandler) OnDelete(obj interface{}) {
@@ -70,13 +95,32 @@ func (h *ResourceEventHandler) OnUpdate(_ interface{}, reqObj interface{}) {
 		h.Log.Error(err, "failed to get object", "object", obj)
 	}
 
-	policy, err := NewPolicyFor(obj)
+	policy, err := NewPolicyFor(obj.DeepCopyObject())
 	if err != nil {
 		h.Log.Error(err, "failed to build policy from watched object", "object", obj)
+		recordSync(h.Syncer, h.GVR, err)
 		return
 	}
+	upstream, downstream, err := h.Downstream(ctx, policy)
+	if err != nil {
+		h.Log.Error(err, "failed to resolve the gateway targeted by the policy", "policy", policy)
+		recordSync(h.Syncer, h.GVR, err)
+		return
+	}
+	if upstream != nil {
+		ForDownstream(policy, upstream, downstream)
+	}
+	if h.Metadata != nil {
+		if err := h.Metadata(ctx, policy); err != nil {
+			h.Log.Error(err, "failed to set the metadata of the policy", "policy", policy)
+			recordSync(h.Syncer, h.GVR, err)
+			return
+		}
+	}
 
-	if err := h.Syncer.SyncPolicy(ctx, h.Client, policy); err != nil {
+	err = h.Syncer.SyncPolicy(ctx, h.Client, policy)
+	if err != nil {
 		h.Log.Error(err, "failed to sync policy", "policy", policy)
 	}
+	recordSync(h.Syncer, h.GVR, err)
 }
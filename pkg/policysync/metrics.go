@@ -0,0 +1,43 @@
+package policysync
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	groupLabel    = "group"
+	versionLabel  = "version"
+	resourceLabel = "resource"
+	resultLabel   = "result"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var policySyncs = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "mgc_policy_sync_total",
+		Help: "Number of attempts to sync a policy to the clusters of its gateway, by policy resource and result",
+	},
+	[]string{groupLabel, versionLabel, resourceLabel, resultLabel},
+)
+
+func init() {
+	metrics.Registry.MustRegister(policySyncs)
+}
+
+// recordSync counts the sync of a policy of the resource as a failure when err is set and a success otherwise. The
+// syncs of the FakeSyncer are not counted as it doesn't sync the policy to any cluster
+func recordSync(syncer Syncer, gvr schema.GroupVersionResource, err error) {
+	if _, fake := syncer.(*FakeSyncer); fake {
+		return
+	}
+	result := resultSuccess
+	if err != nil {
+		result = resultFailure
+	}
+	policySyncs.WithLabelValues(gvr.Group, gvr.Version, gvr.Resource, result).Inc()
+}
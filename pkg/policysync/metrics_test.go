@@ -0,0 +1,38 @@
+//go:build unit
+
+package policysync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRecordSync(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "kuadrant.io", Version: "v1alpha1", Resource: "dnspolicies"}
+
+	syncer := &testSyncer{}
+	recordSync(syncer, gvr, nil)
+	recordSync(syncer, gvr, nil)
+	recordSync(syncer, gvr, errors.New("failed"))
+	// the fake syncer doesn't sync the policies so its syncs aren't counted
+	recordSync(&FakeSyncer{}, gvr, nil)
+
+	if successes := testutil.ToFloat64(policySyncs.WithLabelValues(gvr.Group, gvr.Version, gvr.Resource, resultSuccess)); successes != 2 {
+		t.Errorf("expected 2 successful syncs but got %v", successes)
+	}
+	if failures := testutil.ToFloat64(policySyncs.WithLabelValues(gvr.Group, gvr.Version, gvr.Resource, resultFailure)); failures != 1 {
+		t.Errorf("expected 1 failed sync but got %v", failures)
+	}
+}
+
+type testSyncer struct{}
+
+func (*testSyncer) SyncPolicy(_ context.Context, _ client.Client, _ Policy) error {
+	return nil
+}
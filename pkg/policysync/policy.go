@@ -5,6 +5,8 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
@@ -50,3 +52,38 @@ func NewPolicyFor(obj interface{}) (Policy, error) {
 
 	return policy, nil
 }
+
+// TargetedGateway returns the namespaced name of the gateway targeted by the
+// policy. The namespace of the targetRef defaults to the namespace of the
+// policy. false is returned when the policy targets another kind of resource
+func TargetedGateway(policy Policy) (types.NamespacedName, bool) {
+	targetRef := policy.GetTargetRef()
+	if targetRef == nil ||
+		targetRef.Group != gatewayapiv1.GroupName ||
+		targetRef.Kind != "Gateway" {
+		return types.NamespacedName{}, false
+	}
+	namespace := policy.GetNamespace()
+	if targetRef.Namespace != nil && *targetRef.Namespace != "" {
+		namespace = string(*targetRef.Namespace)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: string(targetRef.Name)}, true
+}
+
+// ForDownstream moves a policy targeting the upstream gateway into the
+// namespace of the downstream gateway, rewriting its targetRef, so that the
+// policy synced to the spokes targets the downstream gateway. Policies
+// targeting other resources are left unchanged
+func ForDownstream(policy Policy, upstream, downstream *gatewayapiv1.Gateway) {
+	target, ok := TargetedGateway(policy)
+	if !ok || target.Namespace != upstream.Namespace || target.Name != upstream.Name {
+		return
+	}
+
+	namespace := gatewayapiv1.Namespace(downstream.Namespace)
+	policy.SetNamespace(downstream.Namespace)
+	policy.UpdateTargetRef(func(targetRef *gatewayapiv1alpha2.PolicyTargetReference) {
+		targetRef.Name = gatewayapiv1.ObjectName(downstream.Name)
+		targetRef.Namespace = &namespace
+	})
+}
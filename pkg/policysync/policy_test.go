@@ -3,6 +3,7 @@ package policysync
 import (
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -100,8 +101,90 @@ func TestUnstructuredPolicy(t *testing.T) {
 	if actualName != "changed-name" {
 		t.Errorf("expected targetRef.Name to be changed-name, got %s", actualName)
 	}
-	actualNamespace := policy.Object["spec"].(map[string]interface{})["targetRef"].(map[string]interface{})["namespace"].(*string)
-	if *actualNamespace != "default" {
-		t.Errorf("expected targetRef.Namespace to be default, got %s", *actualNamespace)
+	actualNamespace := policy.Object["spec"].(map[string]interface{})["targetRef"].(map[string]interface{})["namespace"].(string)
+	if actualNamespace != "default" {
+		t.Errorf("expected targetRef.Namespace to be default, got %s", actualNamespace)
+	}
+	if namespace := unstructuredPolicy.GetTargetRef().Namespace; namespace == nil || *namespace != "default" {
+		t.Errorf("expected targetRef.Namespace to be default, got %v", namespace)
+	}
+}
+
+func TestForDownstream(t *testing.T) {
+	upstream := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod-web", Namespace: "multi-cluster-gateways"},
+	}
+	downstream := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod-web", Namespace: "team-a"},
+	}
+	policy := func(namespace, kind, name, targetNamespace string) *unstructured.Unstructured {
+		targetRef := map[string]interface{}{
+			"name":  name,
+			"kind":  kind,
+			"group": gatewayapiv1.GroupName,
+		}
+		if targetNamespace != "" {
+			targetRef["namespace"] = targetNamespace
+		}
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":      "policy",
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"targetRef": targetRef,
+				},
+			},
+		}
+	}
+	testCases := []struct {
+		name          string
+		policy        *unstructured.Unstructured
+		wantNamespace string
+	}{
+		{
+			name:          "policy targeting the gateway is moved to the downstream namespace",
+			policy:        policy("multi-cluster-gateways", "Gateway", "prod-web", ""),
+			wantNamespace: "team-a",
+		},
+		{
+			name:          "policy targeting the gateway from another namespace is moved to the downstream namespace",
+			policy:        policy("policies", "Gateway", "prod-web", "multi-cluster-gateways"),
+			wantNamespace: "team-a",
+		},
+		{
+			name:          "policy targeting another gateway is unchanged",
+			policy:        policy("multi-cluster-gateways", "Gateway", "other", ""),
+			wantNamespace: "multi-cluster-gateways",
+		},
+		{
+			name:          "policy targeting a gateway of the same name in its own namespace is unchanged",
+			policy:        policy("policies", "Gateway", "prod-web", ""),
+			wantNamespace: "policies",
+		},
+		{
+			name:          "policy targeting a gateway of the same name in another namespace is unchanged",
+			policy:        policy("multi-cluster-gateways", "Gateway", "prod-web", "other"),
+			wantNamespace: "multi-cluster-gateways",
+		},
+		{
+			name:          "policy targeting a route is unchanged",
+			policy:        policy("multi-cluster-gateways", "HTTPRoute", "prod-web", ""),
+			wantNamespace: "multi-cluster-gateways",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			unstructuredPolicy := &UnstructuredPolicy{Unstructured: testCase.policy}
+			ForDownstream(unstructuredPolicy, upstream, downstream)
+			if unstructuredPolicy.GetNamespace() != testCase.wantNamespace {
+				t.Errorf("expected policy namespace %s, got %s", testCase.wantNamespace, unstructuredPolicy.GetNamespace())
+			}
+			targetNamespace := unstructuredPolicy.GetTargetRef().Namespace
+			if testCase.wantNamespace == "team-a" && (targetNamespace == nil || *targetNamespace != "team-a") {
+				t.Errorf("expected targetRef.Namespace to be team-a, got %v", targetNamespace)
+			}
+		})
 	}
 }
@@ -34,17 +34,13 @@ func (p *UnstructuredPolicy) GetTargetRef() *gatewayapiv1alpha2.PolicyTargetRefe
 }
 
 func (p *UnstructuredPolicy) SetTargetRef(targetRef *gatewayapiv1alpha2.PolicyTargetReference) {
-	var namespace *string
-	if targetRef.Namespace != nil {
-		ns := string(*targetRef.Namespace)
-		namespace = &ns
-	}
-
 	asObject := map[string]interface{}{
-		"group":     string(targetRef.Group),
-		"kind":      string(targetRef.Kind),
-		"name":      string(targetRef.Name),
-		"namespace": namespace,
+		"group": string(targetRef.Group),
+		"kind":  string(targetRef.Kind),
+		"name":  string(targetRef.Name),
+	}
+	if targetRef.Namespace != nil {
+		asObject["namespace"] = string(*targetRef.Namespace)
 	}
 
 	spec := p.Object["spec"].(map[string]interface{})
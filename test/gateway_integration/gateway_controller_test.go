@@ -80,15 +80,20 @@ var _ = Describe("GatewayClassController", func() {
 				if err := k8sClient.Get(ctx, gatewayclassType, createdGatewayclass); err != nil {
 					return err
 				}
-				if createdGatewayclass.Status.Conditions[0].Status != metav1.ConditionTrue {
-					return fmt.Errorf("expected createdGatewayclass condition to not be true, got %v", createdGatewayclass.Status.Conditions[0].Status)
+				if !meta.IsStatusConditionTrue(createdGatewayclass.Status.Conditions, string(gatewayapiv1.GatewayClassConditionStatusAccepted)) {
+					return fmt.Errorf("expected createdGatewayclass to be accepted, got %v", createdGatewayclass.Status.Conditions)
 				}
 				return nil
 			}, TestTimeoutMedium, TestRetryIntervalMedium).Should(BeNil())
-			condition = createdGatewayclass.Status.Conditions[0]
-			Expect(len(createdGatewayclass.Status.Conditions)).To(BeEquivalentTo(1))
-			Expect(condition.Type).To(BeEquivalentTo(gatewayapiv1.GatewayClassConditionStatusAccepted))
+			condition = *meta.FindStatusCondition(createdGatewayclass.Status.Conditions, string(gatewayapiv1.GatewayClassConditionStatusAccepted))
+			Expect(len(createdGatewayclass.Status.Conditions)).To(BeEquivalentTo(2))
 			Expect(condition.Reason).To(BeEquivalentTo(gatewayapiv1.GatewayClassConditionStatusAccepted))
+			Expect(condition.ObservedGeneration).To(BeEquivalentTo(createdGatewayclass.Generation))
+
+			// Status SupportedVersion
+			condition = *meta.FindStatusCondition(createdGatewayclass.Status.Conditions, string(gatewayapiv1.GatewayClassConditionStatusSupportedVersion))
+			Expect(condition.Status).To(BeEquivalentTo(metav1.ConditionTrue))
+			Expect(condition.Reason).To(BeEquivalentTo(gatewayapiv1.GatewayClassReasonSupportedVersion))
 		})
 
 		It("should NOT accept a gatewayclass for a different controller", func() {
@@ -123,7 +128,7 @@ var _ = Describe("GatewayClassController", func() {
 			}, TestTimeoutMedium, TestRetryIntervalMedium).Should(BeTrue())
 		})
 
-		It("should NOT accept a gatewayclass that is 'unsupported'", func() {
+		It("should accept a gatewayclass with any name for this controller", func() {
 			gatewayclass.Name = "test-class-name-1"
 			Expect(k8sClient.Create(ctx, gatewayclass)).To(BeNil())
 			createdGatewayclass := &gatewayapiv1.GatewayClass{}
@@ -134,7 +139,7 @@ var _ = Describe("GatewayClassController", func() {
 				return k8sClient.Get(ctx, gatewayclassType, createdGatewayclass)
 			}, TestTimeoutMedium, TestRetryIntervalMedium).Should(BeNil())
 
-			// Status is false
+			// Status is true
 			var condition metav1.Condition
 			Eventually(func() bool {
 				err := k8sClient.Get(ctx, gatewayclassType, createdGatewayclass)
@@ -143,11 +148,14 @@ var _ = Describe("GatewayClassController", func() {
 					log.Log.Error(err, "No errors expected")
 					Fail("No errors expected")
 				}
-				condition = createdGatewayclass.Status.Conditions[0]
-				return condition.Type == string(gatewayapiv1.GatewayClassConditionStatusAccepted) && condition.Status == metav1.ConditionFalse
+				accepted := meta.FindStatusCondition(createdGatewayclass.Status.Conditions, string(gatewayapiv1.GatewayClassConditionStatusAccepted))
+				if accepted == nil {
+					return false
+				}
+				condition = *accepted
+				return condition.Status == metav1.ConditionTrue
 			}, TestTimeoutMedium, TestRetryIntervalMedium).Should(BeTrue())
-			Expect(condition.Reason).To(BeEquivalentTo(gatewayapiv1.GatewayClassReasonInvalidParameters))
-			Expect(condition.Message).To(BeEquivalentTo("Invalid Parameters - Unsupported class name test-class-name-1. Must be one of [kuadrant-multi-cluster-gateway-instance-per-cluster]"))
+			Expect(condition.Reason).To(BeEquivalentTo(gatewayapiv1.GatewayClassReasonAccepted))
 		})
 	})
 })
@@ -393,6 +401,22 @@ var _ = Describe("GatewayController", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 			m1AddressesJsonString := string(m1AddressesJson)
+			conditionsJson, err := json.Marshal([]metav1.Condition{
+				{
+					Type:               string(gatewayapiv1.GatewayConditionAccepted),
+					Status:             metav1.ConditionTrue,
+					LastTransitionTime: metav1.Now(),
+					Reason:             string(gatewayapiv1.GatewayReasonAccepted),
+				},
+				{
+					Type:               string(gatewayapiv1.GatewayConditionProgrammed),
+					Status:             metav1.ConditionTrue,
+					LastTransitionTime: metav1.Now(),
+					Reason:             string(gatewayapiv1.GatewayReasonProgrammed),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			conditionsJsonString := string(conditionsJson)
 			manifest1.Status = ocmworkv1.ManifestWorkStatus{
 				Conditions: []metav1.Condition{
 					{
@@ -420,6 +444,13 @@ var _ = Describe("GatewayController", func() {
 											JsonRaw: &m1AddressesJsonString,
 										},
 									},
+									{
+										Name: "conditions",
+										Value: ocmworkv1.FieldValue{
+											Type:    ocmworkv1.JsonRaw,
+											JsonRaw: &conditionsJsonString,
+										},
+									},
 									{
 										Name: "listenerdefaultAttachedRoutes",
 										Value: ocmworkv1.FieldValue{
@@ -485,6 +516,13 @@ var _ = Describe("GatewayController", func() {
 											JsonRaw: &m2AddressesJsonString,
 										},
 									},
+									{
+										Name: "conditions",
+										Value: ocmworkv1.FieldValue{
+											Type:    ocmworkv1.JsonRaw,
+											JsonRaw: &conditionsJsonString,
+										},
+									},
 									{
 										Name: "listenerdefaultAttachedRoutes",
 										Value: ocmworkv1.FieldValue{
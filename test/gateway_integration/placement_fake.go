@@ -9,6 +9,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/placement"
 )
 
 const (
@@ -70,7 +72,7 @@ func NewTestOCMPlacer() *FakeOCMPlacer {
 	return NewFakeOCMPlacer(TestPlacedGatewayName, TestAttachedRouteName)
 }
 
-func (f FakeOCMPlacer) Place(_ context.Context, _ *gatewayapiv1.Gateway, _ *gatewayapiv1.Gateway, _ ...metav1.Object) (sets.Set[string], error) {
+func (f FakeOCMPlacer) Place(_ context.Context, _ *gatewayapiv1.Gateway, _ *gatewayapiv1.Gateway, _ placement.ClusterCustomiser, _ ...metav1.Object) (sets.Set[string], error) {
 	return nil, nil
 }
 
@@ -111,3 +113,34 @@ func (f FakeOCMPlacer) GetAddresses(ctx context.Context, gateway *gatewayapiv1.G
 	}
 	return gwAddresses, nil
 }
+
+func (f FakeOCMPlacer) GetConditions(_ context.Context, _ *gatewayapiv1.Gateway, _ string) ([]metav1.Condition, error) {
+	return []metav1.Condition{
+		{
+			Type:   string(gatewayapiv1.GatewayConditionAccepted),
+			Status: metav1.ConditionTrue,
+			Reason: string(gatewayapiv1.GatewayReasonAccepted),
+		},
+		{
+			Type:   string(gatewayapiv1.GatewayConditionProgrammed),
+			Status: metav1.ConditionTrue,
+			Reason: string(gatewayapiv1.GatewayReasonProgrammed),
+		},
+	}, nil
+}
+
+func (f FakeOCMPlacer) ListenerConditions(_ context.Context, _ *gatewayapiv1.Gateway, _ string, _ string) ([]metav1.Condition, error) {
+	return []metav1.Condition{}, nil
+}
+
+func (f FakeOCMPlacer) GetSecretAnnotations(_ context.Context, _ *gatewayapiv1.Gateway, _ string) (map[string]map[string]string, error) {
+	return map[string]map[string]string{}, nil
+}
+
+func (f FakeOCMPlacer) Plan(_ context.Context, _ *gatewayapiv1.Gateway, _ *gatewayapiv1.Gateway, _ placement.ClusterCustomiser, _ ...metav1.Object) (*placement.Plan, error) {
+	return &placement.Plan{Clusters: []placement.ClusterPlan{}}, nil
+}
+
+func (f FakeOCMPlacer) Remove(_ context.Context, _ *gatewayapiv1.Gateway) (sets.Set[string], error) {
+	return sets.New[string](), nil
+}
@@ -9,6 +9,7 @@ import (
 	certman "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -16,6 +17,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/Kuadrant/multicluster-gateway-controller/pkg/apis/v1alpha1"
 )
 
 const (
@@ -83,7 +87,7 @@ func AssertNoErrorReconciliation() func(res ctrl.Result, err error, t *testing.T
 
 func GetValidTestClient(initLists ...client.ObjectList) client.WithWatch {
 	return fake.NewClientBuilder().
-		WithStatusSubresource(&gatewayapiv1.Gateway{}, &gatewayapiv1.GatewayClass{}).
+		WithStatusSubresource(&gatewayapiv1.Gateway{}, &gatewayapiv1.GatewayClass{}, &v1alpha1.GatewayClassParameters{}).
 		WithScheme(GetValidTestScheme()).
 		WithLists(initLists...).
 		Build()
@@ -92,8 +96,11 @@ func GetValidTestClient(initLists ...client.ObjectList) client.WithWatch {
 func GetValidTestScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	_ = gatewayapiv1.AddToScheme(scheme)
+	_ = gatewayapiv1beta1.AddToScheme(scheme)
 	_ = corev1.AddToScheme(scheme)
 	_ = certman.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
 	return scheme
 }
 